@@ -0,0 +1,7 @@
+data "jwk_oidc_discovery" "idp" {
+  issuer = "https://accounts.google.com"
+}
+
+output "idp_signing_kids" {
+  value = data.jwk_oidc_discovery.idp.signing_kids
+}
@@ -0,0 +1,16 @@
+resource "jwk_okp_key" "key1" {
+    use = "sig"
+    kid = "sign-ed-1"
+    alg = "EdDSA"
+    crv = "Ed25519"
+}
+
+output "okp_key" {
+  value = jwk_okp_key.key1.json
+  sensitive = true
+}
+
+output "okp_public_key" {
+  value = "${nonsensitive(provider::jwk::public_key(jwk_okp_key.key1.json, "verify-ed-1"))}\n"
+  sensitive = false
+}
@@ -0,0 +1,8 @@
+data "jwk_remote_keyset" "partner" {
+  url     = "https://www.googleapis.com/oauth2/v3/certs"
+  timeout = "10s"
+}
+
+output "partner_kids" {
+  value = data.jwk_remote_keyset.partner.kids
+}
@@ -0,0 +1,17 @@
+resource "jwk_rotating_key" "signing" {
+    kid_prefix      = "sign"
+    kty             = "EC"
+    use             = "sig"
+    alg             = "ES256"
+    rotation_period = "90d"
+    keep_previous   = 1
+}
+
+output "signing_key" {
+    value = jwk_rotating_key.signing.current_json
+    sensitive = true
+}
+
+output "jwks" {
+    value = jwk_rotating_key.signing.public_json
+}
@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/cert"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Validity of the certificate, when 'validity_period' is not given
+const defaultCertificateValidity = "365d"
+
+// Key usages of the certificate
+var certificateKeyUsages = map[string]x509.KeyUsage{
+	"digital_signature":  x509.KeyUsageDigitalSignature,
+	"content_commitment": x509.KeyUsageContentCommitment,
+	"key_encipherment":   x509.KeyUsageKeyEncipherment,
+	"data_encipherment":  x509.KeyUsageDataEncipherment,
+	"key_agreement":      x509.KeyUsageKeyAgreement,
+	"cert_signing":       x509.KeyUsageCertSign,
+	"crl_signing":        x509.KeyUsageCRLSign,
+}
+
+// Extended key usages of the certificate
+var certificateExtKeyUsages = map[string]x509.ExtKeyUsage{
+	"server_auth":      x509.ExtKeyUsageServerAuth,
+	"client_auth":      x509.ExtKeyUsageClientAuth,
+	"code_signing":     x509.ExtKeyUsageCodeSigning,
+	"email_protection": x509.ExtKeyUsageEmailProtection,
+}
+
+// Self-signed certificate of a key resource
+type jwkCertificateModel struct {
+	CommonName     types.String `tfsdk:"common_name"`
+	Organization   types.String `tfsdk:"organization"`
+	ValidityPeriod types.String `tfsdk:"validity_period"`
+	KeyUsages      types.List   `tfsdk:"key_usages"`
+}
+
+// Schema of the 'certificate' block
+func certificateBlock() schema.SingleNestedBlock {
+	usages := make([]string, 0, len(certificateKeyUsages)+len(certificateExtKeyUsages))
+	for usage := range certificateKeyUsages {
+		usages = append(usages, usage)
+	}
+	for usage := range certificateExtKeyUsages {
+		usages = append(usages, usage)
+	}
+	sort.Strings(usages)
+
+	return schema.SingleNestedBlock{
+		Description: "Generates a self-signed X.509 certificate for the key. The certificate is included in `json` " +
+			"as `x5c`, `x5t` and `x5t#S256`, and is kept in the public key.",
+		Attributes: map[string]schema.Attribute{
+			"common_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Common name (CN) of the subject and issuer. Defaults to the Key ID.",
+			},
+			"organization": schema.StringAttribute{
+				Optional:    true,
+				Description: "Organization (O) of the subject and issuer.",
+			},
+			"validity_period": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Validity of the certificate from its creation, as a duration, e.g. `90d` or `720h`. Defaults to `%s`.", defaultCertificateValidity),
+			},
+			"key_usages": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: fmt.Sprintf(
+					"Key usages of the certificate, `%s`. Defaults to `digital_signature` for signing keys, and to "+
+						"`key_encipherment` (RSA) or `key_agreement` (EC) for encryption keys.",
+					strings.Join(usages, "`, `"),
+				),
+			},
+		},
+	}
+}
+
+// Validate the 'certificate' block
+func validateCertificateConfig(ctx context.Context, certificate *jwkCertificateModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if certificate == nil {
+		return diags
+	}
+
+	if !certificate.ValidityPeriod.IsNull() && !certificate.ValidityPeriod.IsUnknown() {
+		if _, err := parsePeriod(certificate.ValidityPeriod.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("certificate").AtName("validity_period"), "Invalid 'validity_period' attribute", err.Error())
+		}
+	}
+
+	if !certificate.KeyUsages.IsNull() && !certificate.KeyUsages.IsUnknown() {
+		var usages []types.String
+		diags.Append(certificate.KeyUsages.ElementsAs(ctx, &usages, false)...)
+
+		for _, usage := range usages {
+			if usage.IsUnknown() {
+				continue
+			}
+			_, isKeyUsage := certificateKeyUsages[usage.ValueString()]
+			_, isExtKeyUsage := certificateExtKeyUsages[usage.ValueString()]
+			if !isKeyUsage && !isExtKeyUsage {
+				diags.AddAttributeError(
+					path.Root("certificate").AtName("key_usages"),
+					"Invalid 'key_usages' attribute",
+					fmt.Sprintf("Unsupported key usage '%s'", usage.ValueString()),
+				)
+			}
+		}
+	}
+
+	return diags
+}
+
+// Create a self-signed certificate for the private key, and include it in the key
+// as 'x5c', 'x5t' and 'x5t#S256'. Returns the PEM encoded certificate.
+func addSelfSignedCertificate(ctx context.Context, key jwk.Key, certificate *jwkCertificateModel) (string, error) {
+	var signer crypto.Signer
+	if err := key.Raw(&signer); err != nil {
+		return "", fmt.Errorf("failed to get private key: %w", err)
+	}
+
+	validityPeriod := defaultCertificateValidity
+	if !certificate.ValidityPeriod.IsNull() {
+		validityPeriod = certificate.ValidityPeriod.ValueString()
+	}
+	validity, err := parsePeriod(validityPeriod)
+	if err != nil {
+		return "", err
+	}
+
+	var usages []string
+	if !certificate.KeyUsages.IsNull() {
+		if diags := certificate.KeyUsages.ElementsAs(ctx, &usages, false); diags.HasError() {
+			return "", fmt.Errorf("invalid key usages")
+		}
+	} else if key.KeyUsage() == "enc" && key.KeyType().String() == "RSA" {
+		usages = []string{"key_encipherment"}
+	} else if key.KeyUsage() == "enc" {
+		usages = []string{"key_agreement"}
+	} else {
+		usages = []string{"digital_signature"}
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	commonName := key.KeyID()
+	if !certificate.CommonName.IsNull() {
+		commonName = certificate.CommonName.ValueString()
+	}
+	subject := pkix.Name{CommonName: commonName}
+	if !certificate.Organization.IsNull() {
+		subject.Organization = []string{certificate.Organization.ValueString()}
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               subject,
+		NotBefore:             now,
+		NotAfter:              now.Add(validity),
+		BasicConstraintsValid: true,
+	}
+	for _, usage := range usages {
+		if keyUsage, ok := certificateKeyUsages[usage]; ok {
+			template.KeyUsage |= keyUsage
+		} else if extKeyUsage, ok := certificateExtKeyUsages[usage]; ok {
+			template.ExtKeyUsage = append(template.ExtKeyUsage, extKeyUsage)
+		} else {
+			return "", fmt.Errorf("unsupported key usage '%s'", usage)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, signer.Public(), signer)
+	if err != nil {
+		return "", fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	if err := setCertificate(key, der); err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
+
+// Set DER encoded certificate to the key as 'x5c', 'x5t' and 'x5t#S256'
+func setCertificate(key jwk.Key, der []byte) error {
+	var chain cert.Chain
+	if err := chain.Add([]byte(base64.StdEncoding.EncodeToString(der))); err != nil {
+		return err
+	}
+	if err := key.Set(jwk.X509CertChainKey, &chain); err != nil {
+		return err
+	}
+
+	sha1Sum := sha1.Sum(der)
+	if err := key.Set(jwk.X509CertThumbprintKey, base64.RawURLEncoding.EncodeToString(sha1Sum[:])); err != nil {
+		return err
+	}
+
+	sha256Sum := sha256.Sum256(der)
+	return key.Set(jwk.X509CertThumbprintS256Key, base64.RawURLEncoding.EncodeToString(sha256Sum[:]))
+}
+
+// PEM encoded first certificate of 'x5c' of the key, or empty if the key has no certificate
+func certificatePEM(key jwk.Key) (string, error) {
+	chain := key.X509CertChain()
+	if chain == nil || chain.Len() == 0 {
+		return "", nil
+	}
+
+	encoded, _ := chain.Get(0)
+	der, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil {
+		return "", fmt.Errorf("invalid certificate in 'x5c': %w", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
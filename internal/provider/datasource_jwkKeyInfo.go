@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Size in bits of the curves of EC and OKP keys
+var curveBits = map[string]int64{
+	"P-256":     256,
+	"P-384":     384,
+	"P-521":     521,
+	"secp256k1": 256,
+	"Ed25519":   256,
+	"Ed448":     448,
+	"X25519":    256,
+	"X448":      448,
+}
+
+// Properties of a key, returned by the 'parse_jwk' function and the jwk_key_info data source
+type keyInfoModel struct {
+	Kty        types.String `tfsdk:"kty"`
+	KID        types.String `tfsdk:"kid"`
+	Use        types.String `tfsdk:"use"`
+	Alg        types.String `tfsdk:"alg"`
+	Crv        types.String `tfsdk:"crv"`
+	Size       types.Int64  `tfsdk:"size"`
+	IsPrivate  types.Bool   `tfsdk:"is_private"`
+	KeyOps     types.List   `tfsdk:"key_ops"`
+	Thumbprint types.String `tfsdk:"thumbprint"`
+	PublicJSON types.String `tfsdk:"public_json"`
+}
+
+// Types of the properties of a key, e.g. of the object returned by 'parse_jwk'
+var keyInfoAttributeTypes = map[string]attr.Type{
+	"kty":         types.StringType,
+	"kid":         types.StringType,
+	"use":         types.StringType,
+	"alg":         types.StringType,
+	"crv":         types.StringType,
+	"size":        types.Int64Type,
+	"is_private":  types.BoolType,
+	"key_ops":     types.ListType{ElemType: types.StringType},
+	"thumbprint":  types.StringType,
+	"public_json": types.StringType,
+}
+
+// Object of the properties, as returned by 'parse_jwk'
+func (m keyInfoModel) objectValue(ctx context.Context) (types.Object, diag.Diagnostics) {
+	return types.ObjectValueFrom(ctx, keyInfoAttributeTypes, m)
+}
+
+// Parse and validate a key, and get its properties
+func parseKeyInfo(ctx context.Context, keyJSON string) (keyInfoModel, error) {
+	key, err := json2jwk(keyJSON)
+	if err != nil {
+		return keyInfoModel{}, err
+	}
+	if err := key.Validate(); err != nil {
+		return keyInfoModel{}, fmt.Errorf("invalid JWK: %w", err)
+	}
+
+	alg := ""
+	if key.Algorithm() != nil {
+		alg = key.Algorithm().String()
+	}
+
+	ops := make([]string, 0, len(key.KeyOps()))
+	for _, op := range key.KeyOps() {
+		ops = append(ops, string(op))
+	}
+	keyOps, diags := types.ListValueFrom(ctx, types.StringType, ops)
+	if diags.HasError() {
+		return keyInfoModel{}, fmt.Errorf("invalid key_ops")
+	}
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		return keyInfoModel{}, err
+	}
+
+	// Symmetric keys have no public form
+	publicJSON := ""
+	if key.KeyType() != jwa.OctetSeq {
+		publicKey, err := key.PublicKey()
+		if err != nil {
+			return keyInfoModel{}, fmt.Errorf("failed to extract public key: %w", err)
+		}
+		publicBytes, err := json.Marshal(publicKey)
+		if err != nil {
+			return keyInfoModel{}, fmt.Errorf("failed to serialize public key to JSON: %w", err)
+		}
+		publicJSON = string(publicBytes)
+	}
+
+	return keyInfoModel{
+		Kty:        types.StringValue(key.KeyType().String()),
+		KID:        types.StringValue(key.KeyID()),
+		Use:        types.StringValue(key.KeyUsage()),
+		Alg:        types.StringValue(alg),
+		Crv:        types.StringValue(keyCurve(key)),
+		Size:       types.Int64Value(keySizeBits(key)),
+		IsPrivate:  types.BoolValue(isPrivateJWK(key)),
+		KeyOps:     keyOps,
+		Thumbprint: types.StringValue(thumbprint),
+		PublicJSON: types.StringValue(publicJSON),
+	}, nil
+}
+
+// Size of the key in bits: the modulus of RSA keys, the curve of EC and OKP keys and the key of oct keys
+func keySizeBits(key jwk.Key) int64 {
+	switch key.KeyType() {
+	case jwa.RSA:
+		return int64(rsaKeyBits(key))
+	case jwa.EC, jwa.OKP:
+		return curveBits[keyCurve(key)]
+	case jwa.OctetSeq:
+		var raw []byte
+		if err := key.Raw(&raw); err == nil {
+			return int64(len(raw) * 8)
+		}
+	}
+	return 0
+}
+
+// Creates a new instance of the jwkKeyInfoDataSource.
+func NewJwkKeyInfoDataSource() datasource.DataSource {
+	return &jwkKeyInfoDataSource{}
+}
+
+// jwkKeyInfoDataSource inspects a key given in Json format of JWK, e.g. a key of another team
+type jwkKeyInfoDataSource struct{}
+
+type jwkKeyInfoDataSourceModel struct {
+	KeyJSON types.String `tfsdk:"json"`
+	keyInfoModel
+}
+
+// Data source Documentation
+func (d *jwkKeyInfoDataSource) Documentation() string {
+	return `Parses and validates a key given in Json format of JWK, and exposes its properties, e.g. to branch on them in modules.
+The same properties are returned by the 'parse_jwk' function.`
+}
+
+// Data source Metadata
+func (d *jwkKeyInfoDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "jwk_key_info"
+}
+
+// Data source Schema
+func (d *jwkKeyInfoDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: d.Documentation(),
+
+		Attributes: map[string]schema.Attribute{
+			"json": schema.StringAttribute{
+				Required:    true,
+				Sensitive:   true,
+				Description: "The JSON representation of the key. Private keys are accepted, only their public properties are exposed.",
+			},
+			"kty": schema.StringAttribute{
+				Computed:    true,
+				Description: "Key type, such as `RSA`, `EC`, `OKP` or `oct`.",
+			},
+			"kid": schema.StringAttribute{
+				Computed:    true,
+				Description: "Key ID of the key. Empty, if not given.",
+			},
+			"use": schema.StringAttribute{
+				Computed:    true,
+				Description: "Intended use of the key, `sig` or `enc`. Empty, if not given.",
+			},
+			"alg": schema.StringAttribute{
+				Computed:    true,
+				Description: "Algorithm of the key. Empty, if not given.",
+			},
+			"crv": schema.StringAttribute{
+				Computed:    true,
+				Description: "Curve of EC and OKP keys. Empty for other key types.",
+			},
+			"size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Size of the key in bits: the modulus of `RSA` keys, the curve of `EC` and `OKP` keys, and the key of `oct` keys.",
+			},
+			"is_private": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the key contains private key material. Always `true` for `oct` keys.",
+			},
+			"key_ops": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Permitted operations of the key, such as `sign` or `verify`. Empty, if not given.",
+			},
+			"thumbprint": schema.StringAttribute{
+				Computed:    true,
+				Description: "The RFC 7638 JWK Thumbprint of the key (base64url encoded SHA-256).",
+			},
+			"public_json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The JSON representation of the public key. Empty for `oct` keys, which have no public form.",
+			},
+		},
+	}
+}
+
+// Read
+func (d *jwkKeyInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model jwkKeyInfoDataSourceModel
+
+	diags := req.Config.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	info, err := parseKeyInfo(ctx, model.KeyJSON.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("json"), "Invalid JWK", err.Error())
+		return
+	}
+	model.keyInfoModel = info
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
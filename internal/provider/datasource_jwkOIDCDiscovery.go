@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Path of the OpenID Provider configuration document, relative to the issuer
+const oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+// Creates a new instance of the jwkOIDCDiscoveryDataSource.
+func NewJwkOIDCDiscoveryDataSource() datasource.DataSource {
+	return &jwkOIDCDiscoveryDataSource{}
+}
+
+// jwkOIDCDiscoveryDataSource retrieves OpenID Provider metadata and its signing keys.
+type jwkOIDCDiscoveryDataSource struct{}
+
+// This struct gets populated with the configuration values
+type jwkOIDCDiscoveryModel struct {
+	Issuer                  types.String      `tfsdk:"issuer"`
+	Timeout                 types.String      `tfsdk:"timeout"`
+	CABundle                types.String      `tfsdk:"ca_bundle"`
+	Headers                 types.Map         `tfsdk:"headers"`
+	JwksURI                 types.String      `tfsdk:"jwks_uri"`
+	AuthorizationEndpoint   types.String      `tfsdk:"authorization_endpoint"`
+	TokenEndpoint           types.String      `tfsdk:"token_endpoint"`
+	UserinfoEndpoint        types.String      `tfsdk:"userinfo_endpoint"`
+	EndSessionEndpoint      types.String      `tfsdk:"end_session_endpoint"`
+	IDTokenSigningAlgValues types.List        `tfsdk:"id_token_signing_alg_values_supported"`
+	MetadataJSON            types.String      `tfsdk:"metadata_json"`
+	KeysetJSON              types.String      `tfsdk:"jwks_json"`
+	SigningKIDs             types.List        `tfsdk:"signing_kids"`
+	SigningKeys             []jwkKeyInfoModel `tfsdk:"signing_keys"`
+}
+
+// Subset of OpenID Provider metadata, see OpenID Connect Discovery 1.0, section 3
+type oidcProviderMetadata struct {
+	Issuer                  string   `json:"issuer"`
+	JwksURI                 string   `json:"jwks_uri"`
+	AuthorizationEndpoint   string   `json:"authorization_endpoint"`
+	TokenEndpoint           string   `json:"token_endpoint"`
+	UserinfoEndpoint        string   `json:"userinfo_endpoint"`
+	EndSessionEndpoint      string   `json:"end_session_endpoint"`
+	IDTokenSigningAlgValues []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// Data source Documentation
+func (d *jwkOIDCDiscoveryDataSource) Documentation() string {
+	return `Retrieves OpenID Provider metadata from '<issuer>/.well-known/openid-configuration' (OpenID Connect Discovery 1.0).
+The 'issuer' in the metadata must match the configured issuer. The key set referred by 'jwks_uri' is fetched,
+and its signing keys are parsed and validated. The 'headers' are sent with the request of the key set only,
+when 'jwks_uri' has the same origin (scheme, host and port) as the issuer.`
+}
+
+// Data source Metadata
+func (d *jwkOIDCDiscoveryDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "jwk_oidc_discovery"
+}
+
+// Data source Schema
+func (d *jwkOIDCDiscoveryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: d.Documentation(),
+
+		Attributes: map[string]schema.Attribute{
+			"issuer": schema.StringAttribute{
+				Required:    true,
+				Description: "The issuer identifier URL of the OpenID Provider, e.g. `https://login.example.com`.",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Timeout of each request as a duration, e.g. `10s`. Defaults to `%s`.", defaultHTTPTimeout),
+			},
+			"ca_bundle": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded CA certificates used to verify the server certificates. System roots are used, if not given.",
+			},
+			"headers": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: "Additional HTTP headers sent with the requests, e.g. `Authorization`. They are not sent to a `jwks_uri` of another origin than the issuer, nor on a redirect to another origin.",
+			},
+			"jwks_uri": schema.StringAttribute{
+				Computed:    true,
+				Description: "URL of the JWK Set document of the OpenID Provider.",
+			},
+			"authorization_endpoint": schema.StringAttribute{
+				Computed:    true,
+				Description: "URL of the OAuth 2.0 Authorization Endpoint.",
+			},
+			"token_endpoint": schema.StringAttribute{
+				Computed:    true,
+				Description: "URL of the OAuth 2.0 Token Endpoint.",
+			},
+			"userinfo_endpoint": schema.StringAttribute{
+				Computed:    true,
+				Description: "URL of the UserInfo Endpoint.",
+			},
+			"end_session_endpoint": schema.StringAttribute{
+				Computed:    true,
+				Description: "URL of the RP-Initiated Logout Endpoint.",
+			},
+			"id_token_signing_alg_values_supported": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Signing algorithms supported for ID Tokens.",
+			},
+			"metadata_json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The complete OpenID Provider metadata document. Use `jsondecode()` to access other members.",
+			},
+			"jwks_json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The JSON representation of the JWK key set referred by `jwks_uri`.",
+			},
+			"signing_kids": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Key IDs of the signing keys.",
+			},
+			"signing_keys": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The signing keys of the key set, i.e. keys with `use` of `sig` or without `use`.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: jwkKeyInfoAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// Read
+func (d *jwkOIDCDiscoveryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model jwkOIDCDiscoveryModel
+
+	diags := req.Config.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	opts, diags := httpOptionsFromConfig(ctx, model.Timeout, model.CABundle, model.Headers)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	issuer := model.Issuer.ValueString()
+	metadataJSON, err := fetchURL(ctx, strings.TrimSuffix(issuer, "/")+oidcDiscoveryPath, opts)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("issuer"), "Failed to fetch OpenID Provider metadata", err.Error())
+		return
+	}
+
+	var metadata oidcProviderMetadata
+	if err := json.Unmarshal(metadataJSON, &metadata); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("issuer"), "Invalid OpenID Provider metadata", err.Error())
+		return
+	}
+
+	// The issuer must be identical to the one used to retrieve the configuration
+	if metadata.Issuer != issuer {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("issuer"),
+			"Issuer mismatch",
+			fmt.Sprintf("OpenID Provider metadata contains issuer '%s', expected '%s'", metadata.Issuer, issuer),
+		)
+		return
+	}
+
+	if metadata.JwksURI == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("issuer"), "Invalid OpenID Provider metadata", "Metadata does not contain 'jwks_uri'")
+		return
+	}
+
+	// The metadata may refer to any host, which must not receive the credentials of the issuer
+	jwksOpts := opts
+	if !sameOrigin(issuer, metadata.JwksURI) {
+		jwksOpts.Headers = nil
+	}
+
+	body, err := fetchURL(ctx, metadata.JwksURI, jwksOpts)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to fetch JWK Set", err.Error())
+		return
+	}
+
+	keys, raws, err := parseJWKSet(body)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK Set", fmt.Sprintf("%s: %s", metadata.JwksURI, err.Error()))
+		return
+	}
+
+	keysetJSON, err := json.Marshal(JWKKeyset{Keys: raws})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to marshal keyset", err.Error())
+		return
+	}
+
+	// Pick the signing keys
+	signingKeys := make([]jwk.Key, 0, len(keys))
+	signingRaws := make([]json.RawMessage, 0, len(keys))
+	signingKIDs := make([]string, 0, len(keys))
+	for i, key := range keys {
+		if key.KeyUsage() == "" || key.KeyUsage() == "sig" {
+			signingKeys = append(signingKeys, key)
+			signingRaws = append(signingRaws, raws[i])
+			signingKIDs = append(signingKIDs, key.KeyID())
+		}
+	}
+
+	model.IDTokenSigningAlgValues, diags = types.ListValueFrom(ctx, types.StringType, metadata.IDTokenSigningAlgValues)
+	resp.Diagnostics.Append(diags...)
+	model.SigningKIDs, diags = types.ListValueFrom(ctx, types.StringType, signingKIDs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.JwksURI = types.StringValue(metadata.JwksURI)
+	model.AuthorizationEndpoint = types.StringValue(metadata.AuthorizationEndpoint)
+	model.TokenEndpoint = types.StringValue(metadata.TokenEndpoint)
+	model.UserinfoEndpoint = types.StringValue(metadata.UserinfoEndpoint)
+	model.EndSessionEndpoint = types.StringValue(metadata.EndSessionEndpoint)
+	model.MetadataJSON = types.StringValue(string(metadataJSON))
+	model.KeysetJSON = types.StringValue(string(keysetJSON))
+	model.SigningKeys = jwkKeyInfoModels(signingKeys, signingRaws)
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Default timeout for fetching remote documents
+const defaultHTTPTimeout = "30s"
+
+// Creates a new instance of the jwkRemoteKeysetDataSource.
+func NewJwkRemoteKeysetDataSource() datasource.DataSource {
+	return &jwkRemoteKeysetDataSource{}
+}
+
+// jwkRemoteKeysetDataSource fetches a JWK Set (JWKS) from a remote URL, e.g. jwks_uri of an issuer.
+type jwkRemoteKeysetDataSource struct{}
+
+// This struct gets populated with the configuration values
+type jwkRemoteKeysetModel struct {
+	URL        types.String      `tfsdk:"url"`
+	Timeout    types.String      `tfsdk:"timeout"`
+	CABundle   types.String      `tfsdk:"ca_bundle"`
+	Headers    types.Map         `tfsdk:"headers"`
+	KeysetJSON types.String      `tfsdk:"json"`
+	KIDs       types.List        `tfsdk:"kids"`
+	Keys       []jwkKeyInfoModel `tfsdk:"keys"`
+}
+
+// Properties of a single key in a fetched key set
+type jwkKeyInfoModel struct {
+	KID     types.String `tfsdk:"kid"`
+	Kty     types.String `tfsdk:"kty"`
+	Use     types.String `tfsdk:"use"`
+	Alg     types.String `tfsdk:"alg"`
+	Crv     types.String `tfsdk:"crv"`
+	KeyJSON types.String `tfsdk:"json"`
+}
+
+// Schema of a single key in a fetched key set
+func jwkKeyInfoAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"kid": schema.StringAttribute{
+			Computed:    true,
+			Description: "Key ID of the key.",
+		},
+		"kty": schema.StringAttribute{
+			Computed:    true,
+			Description: "Key type, such as `RSA`, `EC`, `OKP` or `oct`.",
+		},
+		"use": schema.StringAttribute{
+			Computed:    true,
+			Description: "Intended use of the key, `sig` or `enc`. Empty, if not given.",
+		},
+		"alg": schema.StringAttribute{
+			Computed:    true,
+			Description: "Algorithm of the key. Empty, if not given.",
+		},
+		"crv": schema.StringAttribute{
+			Computed:    true,
+			Description: "Curve of EC and OKP keys. Empty for other key types.",
+		},
+		"json": schema.StringAttribute{
+			Computed:    true,
+			Description: "The JSON representation of the key.",
+		},
+	}
+}
+
+// Convert parsed keys to models
+func jwkKeyInfoModels(keys []jwk.Key, raws []json.RawMessage) []jwkKeyInfoModel {
+	models := make([]jwkKeyInfoModel, 0, len(keys))
+	for i, key := range keys {
+		alg := ""
+		if key.Algorithm() != nil {
+			alg = key.Algorithm().String()
+		}
+
+		models = append(models, jwkKeyInfoModel{
+			KID:     types.StringValue(key.KeyID()),
+			Kty:     types.StringValue(key.KeyType().String()),
+			Use:     types.StringValue(key.KeyUsage()),
+			Alg:     types.StringValue(alg),
+			Crv:     types.StringValue(keyCurve(key)),
+			KeyJSON: types.StringValue(string(raws[i])),
+		})
+	}
+	return models
+}
+
+// Data source Documentation
+func (d *jwkRemoteKeysetDataSource) Documentation() string {
+	return `Fetches a JSON Web Key Set (JWKS) from a remote URL, such as the 'jwks_uri' of an identity provider.
+Every key in the set is parsed and validated. The key set can be combined with keys managed by this provider,
+e.g. in 'jwk_keyset'.`
+}
+
+// Data source Metadata
+func (d *jwkRemoteKeysetDataSource) Metadata(_ context.Context, _ datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "jwk_remote_keyset"
+}
+
+// Data source Schema
+func (d *jwkRemoteKeysetDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: d.Documentation(),
+
+		Attributes: map[string]schema.Attribute{
+			"url": schema.StringAttribute{
+				Required:    true,
+				Description: "The URL of the JWK Set document, e.g. `https://example.com/.well-known/jwks.json`.",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Timeout of the request as a duration, e.g. `10s`. Defaults to `%s`.", defaultHTTPTimeout),
+			},
+			"ca_bundle": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded CA certificates used to verify the server certificate. System roots are used, if not given.",
+			},
+			"headers": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: "Additional HTTP headers sent with the request, e.g. `Authorization`. They are not sent on a redirect to another origin.",
+			},
+			"json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The JSON representation of the fetched JWK key set.",
+			},
+			"kids": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Key IDs of the keys in the key set, in the order they appear in the set.",
+			},
+			"keys": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The keys in the key set.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: jwkKeyInfoAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// Read
+func (d *jwkRemoteKeysetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model jwkRemoteKeysetModel
+
+	diags := req.Config.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	opts, diags := httpOptionsFromConfig(ctx, model.Timeout, model.CABundle, model.Headers)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body, err := fetchURL(ctx, model.URL.ValueString(), opts)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("url"), "Failed to fetch JWK Set", err.Error())
+		return
+	}
+
+	keys, raws, err := parseJWKSet(body)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("url"), "Invalid JWK Set", err.Error())
+		return
+	}
+
+	keysetJSON, err := json.Marshal(JWKKeyset{Keys: raws})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to marshal keyset", err.Error())
+		return
+	}
+
+	kids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		kids = append(kids, key.KeyID())
+	}
+
+	model.KIDs, diags = types.ListValueFrom(ctx, types.StringType, kids)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.KeysetJSON = types.StringValue(string(keysetJSON))
+	model.Keys = jwkKeyInfoModels(keys, raws)
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Build HTTP options from data source configuration
+func httpOptionsFromConfig(ctx context.Context, timeout, caBundle types.String, headers types.Map) (httpOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	timeoutStr := defaultHTTPTimeout
+	if !timeout.IsNull() {
+		timeoutStr = timeout.ValueString()
+	}
+
+	duration, err := time.ParseDuration(timeoutStr)
+	if err != nil || duration <= 0 {
+		diags.AddAttributeError(
+			path.Root("timeout"),
+			"Invalid 'timeout' attribute",
+			fmt.Sprintf("Expected a positive duration such as '30s', got '%s'", timeoutStr),
+		)
+	}
+
+	headerValues := map[string]string{}
+	if !headers.IsNull() {
+		diags.Append(headers.ElementsAs(ctx, &headerValues, false)...)
+	}
+
+	return httpOptions{
+		Timeout:  duration,
+		CABundle: caBundle.ValueString(),
+		Headers:  headerValues,
+	}, diags
+}
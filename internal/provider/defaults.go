@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Variables of 'kid_template', written as ${name}
+var validKIDTemplateVariables = []string{"prefix", "kty", "use", "alg", "crv", "date", "timestamp", "thumbprint", "uuid"}
+
+var kidTemplateVariable = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// Defaults of the key resources, configured by 'defaults' of the provider. A nil
+// *keyDefaults has no defaults, so attributes must be given in the resources.
+type keyDefaults struct {
+	use         string
+	algorithms  map[string]string // Algorithm per key type
+	rsaSize     int64
+	ecCurve     string
+	kidPrefix   string
+	kidTemplate string
+}
+
+// Create key defaults from the provider configuration, reporting invalid values on their attributes
+func newKeyDefaults(ctx context.Context, model *keyDefaultsModel) (*keyDefaults, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	root := path.Root("defaults")
+
+	defaults := &keyDefaults{
+		use:         model.Use.ValueString(),
+		algorithms:  map[string]string{},
+		rsaSize:     model.RSASize.ValueInt64(),
+		ecCurve:     model.ECCurve.ValueString(),
+		kidPrefix:   model.KIDPrefix.ValueString(),
+		kidTemplate: model.KIDTemplate.ValueString(),
+	}
+
+	if defaults.use != "" && !isValid(defaults.use, validUses) {
+		diags.AddAttributeError(root.AtName("use"), "Invalid attribute value for 'use'",
+			fmt.Sprintf("Expected 'sig' or 'enc', got '%s'", defaults.use))
+	}
+
+	diags.Append(model.Algorithms.ElementsAs(ctx, &defaults.algorithms, false)...)
+	for _, kty := range keys(defaults.algorithms) {
+		alg := defaults.algorithms[kty]
+		algorithms, ok := keyTypeAlgorithms[kty]
+		if !ok {
+			diags.AddAttributeError(root.AtName("algorithms").AtMapKey(kty), "Invalid key type in 'algorithms'",
+				fmt.Sprintf("Expected one of %v, got '%s'", validKeyTypes, kty))
+			continue
+		}
+		if _, isSig := algorithms["sig"][alg]; !isSig {
+			if _, isEnc := algorithms["enc"][alg]; !isEnc {
+				diags.AddAttributeError(root.AtName("algorithms").AtMapKey(kty), "Invalid algorithm in 'algorithms'",
+					fmt.Sprintf("Expected one of %v or %v for key type '%s', got '%s'",
+						keys(algorithms["sig"]), keys(algorithms["enc"]), kty, alg))
+			}
+		}
+	}
+
+	if !model.RSASize.IsNull() && defaults.rsaSize < 2048 {
+		diags.AddAttributeError(root.AtName("rsa_size"), "Invalid attribute value for 'rsa_size'",
+			fmt.Sprintf("size must be at least 2048, got '%d'", defaults.rsaSize))
+	}
+
+	if defaults.ecCurve != "" && !isValid(defaults.ecCurve, validECCurves) {
+		diags.AddAttributeError(root.AtName("ec_curve"), "Invalid attribute value for 'ec_curve'",
+			fmt.Sprintf("Expected one of '%s', got '%s'", strings.Join(validECCurves, ", "), defaults.ecCurve))
+	}
+
+	for _, match := range kidTemplateVariable.FindAllStringSubmatch(defaults.kidTemplate, -1) {
+		if !isValid(match[1], validKIDTemplateVariables) {
+			diags.AddAttributeError(root.AtName("kid_template"), "Invalid variable in 'kid_template'",
+				fmt.Sprintf("Expected one of ${%s}, got '%s'", strings.Join(validKIDTemplateVariables, "}, ${"), match[0]))
+		}
+	}
+
+	return defaults, diags
+}
+
+// Default 'use' of the keys, null when not configured
+func (d *keyDefaults) useValue() types.String {
+	if d == nil || d.use == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(d.use)
+}
+
+// Default algorithm of the key type, null when not configured or not suitable for the use of the key.
+// Unknown, while the use of the key is unknown.
+func (d *keyDefaults) algValue(kty string, use types.String) types.String {
+	if d == nil {
+		return types.StringNull()
+	}
+	if use.IsUnknown() {
+		return types.StringUnknown()
+	}
+
+	alg, ok := d.algorithms[kty]
+	if !ok {
+		return types.StringNull()
+	}
+	if _, ok := keyTypeAlgorithms[kty][use.ValueString()][alg]; !ok {
+		return types.StringNull()
+	}
+	return types.StringValue(alg)
+}
+
+// Default size of RSA keys, null when not configured
+func (d *keyDefaults) rsaSizeValue() types.Int64 {
+	if d == nil || d.rsaSize == 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(d.rsaSize)
+}
+
+// Default curve of EC keys, null when not configured
+func (d *keyDefaults) ecCurveValue() types.String {
+	if d == nil || d.ecCurve == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(d.ecCurve)
+}
+
+// Set generated Key ID to the key, unless the Key ID is given in configuration. Without
+// 'kid_strategy', the Key ID is generated from 'kid_template', when it is configured.
+func (d *keyDefaults) setComputedKID(key jwk.Key, kid, strategy types.String) error {
+	if d == nil || d.kidTemplate == "" || !strategy.IsNull() || (!kid.IsNull() && !kid.IsUnknown()) {
+		return setComputedKID(key, kid, strategy)
+	}
+
+	generated, err := d.expandKIDTemplate(key)
+	if err != nil {
+		return err
+	}
+
+	return key.Set(jwk.KeyIDKey, generated)
+}
+
+// Expand the variables of 'kid_template' for the key
+func (d *keyDefaults) expandKIDTemplate(key jwk.Key) (string, error) {
+	var err error
+
+	kid := kidTemplateVariable.ReplaceAllStringFunc(d.kidTemplate, func(variable string) string {
+		var value string
+		switch name := variable[2 : len(variable)-1]; name {
+		case "prefix":
+			value = d.kidPrefix
+		case "kty":
+			value = key.KeyType().String()
+		case "use":
+			value = key.KeyUsage()
+		case "alg":
+			value = key.Algorithm().String()
+		case "crv":
+			value = keyCurve(key)
+		case "date":
+			value = time.Now().UTC().Format("20060102")
+		case "timestamp":
+			value = strconv.FormatInt(time.Now().Unix(), 10)
+		case "thumbprint":
+			value, err = jwkThumbprint(key, defaultThumbprintHash)
+		case "uuid":
+			value, err = uuid.GenerateUUID()
+		default:
+			err = fmt.Errorf("unsupported variable '%s' in kid template", variable)
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if kid == "" {
+		return "", fmt.Errorf("kid template '%s' expands to an empty Key ID", d.kidTemplate)
+	}
+	return kid, nil
+}
+
+// Planned value of an attribute with a provider default. The default applies, when the
+// attribute is not configured. An unknown configuration keeps the planned value.
+func defaultString(config, plan, value types.String) types.String {
+	if !config.IsNull() {
+		return plan
+	}
+	return value
+}
+
+// Planned value of a number attribute with a provider default, see defaultString
+func defaultInt64(config, plan, value types.Int64) types.Int64 {
+	if !config.IsNull() {
+		return plan
+	}
+	return value
+}
+
+// Report an attribute, which is neither given in the resource nor in 'defaults' of the provider
+func addMissingDefaultError(diags *diag.Diagnostics, name, defaultName string) {
+	diags.AddAttributeError(
+		path.Root(name),
+		fmt.Sprintf("Missing '%s' attribute", name),
+		fmt.Sprintf("Give '%s' in the resource, or '%s' in 'defaults' of the provider.", name, defaultName),
+	)
+}
@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Creates a new instance of the jwkECKeyEphemeralResource.
+func NewJwkECKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &jwkECKeyEphemeralResource{}
+}
+
+// jwkECKeyEphemeralResource generates an EC key on every run without storing it in state.
+type jwkECKeyEphemeralResource struct {
+	providerData *jwkProviderData
+}
+
+// This struct gets populated with the configuration values
+type jwkECKeyEphemeralModel struct {
+	KID                types.String `tfsdk:"kid"`
+	KIDStrategy        types.String `tfsdk:"kid_strategy"`
+	Use                types.String `tfsdk:"use"`
+	Crv                types.String `tfsdk:"crv"`
+	Alg                types.String `tfsdk:"alg"`
+	KeyJSON            types.String `tfsdk:"json"`
+	PublicKeyJSON      types.String `tfsdk:"public_json"`
+	Thumbprint         types.String `tfsdk:"thumbprint"`
+	PrivateKeyPEM      types.String `tfsdk:"private_key_pem"`
+	PrivateKeyPEMPKCS8 types.String `tfsdk:"private_key_pem_pkcs8"`
+	PublicKeyPEM       types.String `tfsdk:"public_key_pem"`
+}
+
+// Ephemeral Resource Documentation
+func (r *jwkECKeyEphemeralResource) Documentation() string {
+	return `This ephemeral resource generates an EC key for JSON Web Key (JWK) purposes on every run.
+The key is never stored in state or plan, so it can be handed to write-only attributes, e.g. of a secrets manager.
+Persist only the public key ('public_json') or the 'thumbprint' in regular resources. Requires Terraform 1.10 or later.`
+}
+
+// Ephemeral Resource Metadata
+func (r *jwkECKeyEphemeralResource) Metadata(_ context.Context, _ ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = "jwk_ec_key"
+}
+
+// Ephemeral Resource Configure
+func (r *jwkECKeyEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	r.providerData = resourceProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+// Ephemeral Resource Schema
+func (r *jwkECKeyEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	sigAlgs := keys(ECSigAlgorithms)
+	encAlgs := keys(ECEncAlgorithms)
+
+	resp.Schema = schema.Schema{
+		Description: r.Documentation(),
+
+		Attributes: map[string]schema.Attribute{
+			"kid": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "The Key ID (KID) is a unique identifier for the key. It is used to distinguish different keys in a key set. " +
+					"If not given, the Key ID is generated according to `kid_strategy`.",
+			},
+			"kid_strategy": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"Strategy used to generate the Key ID, when `kid` is not given. `%s`. Defaults to `%s`.",
+					strings.Join(validKIDStrategies, "`, `"), defaultKIDStrategy,
+				),
+				Validators: []validator.String{stringOneOf(validKIDStrategies...)},
+			},
+			"use": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Specifies the intended use of the key. Allowed values: `sig` (for signing) and `enc` (for encryption). " +
+					"Required, unless `use` is given in `defaults` of the provider.",
+				Validators: []validator.String{stringOneOf(validUses...)},
+			},
+			"crv": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Elliptic curve used for the key. Common values include `P-256`, `P-384`, and `P-521`. `secp256k1` is available for `ES256K` signing keys. " +
+					"Defaults to the curve required by `alg` for signing keys, otherwise to `ec_curve` in `defaults` of the provider.",
+				Validators: []validator.String{stringOneOf(validECCurves...)},
+			},
+			"alg": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: fmt.Sprintf(
+					"The cryptographic algorithm associated with the key. `%s` for signing, `%s` for encryption. "+
+						"Defaults to the algorithm for `EC` in `defaults` of the provider, when it suits `use`.",
+					strings.Join(sigAlgs, "`, `"), strings.Join(encAlgs, "`, `"),
+				),
+			},
+			"json": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The JSON representation of the key in JWK (JSON Web Key) format.",
+			},
+			"public_json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The JSON representation of the public key in JWK format.",
+			},
+			"thumbprint": schema.StringAttribute{
+				Computed:    true,
+				Description: "The RFC 7638 JWK Thumbprint of the key (base64url encoded SHA-256).",
+			},
+			"private_key_pem": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The private key in PEM format, SEC 1 (`EC PRIVATE KEY`). Empty for `secp256k1` keys, for which PEM encoding is not supported.",
+			},
+			"private_key_pem_pkcs8": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The private key in PEM format, PKCS#8 (`PRIVATE KEY`). Empty for `secp256k1` keys, for which PEM encoding is not supported.",
+			},
+			"public_key_pem": schema.StringAttribute{
+				Computed:    true,
+				Description: "The public key in PEM format, PKIX SubjectPublicKeyInfo (`PUBLIC KEY`). Empty for `secp256k1` keys, for which PEM encoding is not supported.",
+			},
+		},
+	}
+}
+
+// Open generates the key
+func (r *jwkECKeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var model jwkECKeyEphemeralModel
+
+	diags := req.Config.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config := model
+	defaults := r.providerData.keyDefaults()
+	model.Use = defaultString(config.Use, model.Use, defaults.useValue())
+	model.Alg = defaultString(config.Alg, model.Alg, defaults.algValue("EC", model.Use))
+
+	// The default algorithm of signing keys applies only, when it suits the given curve
+	if config.Alg.IsNull() && !config.Crv.IsNull() && !model.Alg.IsNull() && model.Use.ValueString() == "sig" &&
+		ECSigningAlgorithmsToCurves[model.Alg.ValueString()] != config.Crv.ValueString() {
+		model.Alg = types.StringNull()
+	}
+
+	// Signing algorithms require a curve, otherwise the default curve applies
+	crv := defaults.ecCurveValue()
+	if expectedCrv, ok := ECSigningAlgorithmsToCurves[model.Alg.ValueString()]; ok && model.Use.ValueString() == "sig" {
+		crv = types.StringValue(expectedCrv)
+	}
+	model.Crv = defaultString(config.Crv, model.Crv, crv)
+
+	if model.Use.IsNull() {
+		addMissingDefaultError(&resp.Diagnostics, "use", "use")
+	}
+	if model.Crv.IsNull() {
+		addMissingDefaultError(&resp.Diagnostics, "crv", "ec_curve")
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checks skipped in ValidateConfig, as the values were not known without the defaults
+	if config.Use.IsNull() || config.Alg.IsNull() || config.Crv.IsNull() {
+		resp.Diagnostics.Append(validateECKeyConfig(jwkECKeyModel{
+			KID:         model.KID,
+			KIDStrategy: model.KIDStrategy,
+			Use:         model.Use,
+			Crv:         model.Crv,
+			Alg:         model.Alg,
+		})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Policy of the provider applies to ephemeral keys as well
+	resp.Diagnostics.Append(r.providerData.keyPolicy().checkKey(policyKey{
+		kty: "EC",
+		use: model.Use.ValueString(),
+		alg: model.Alg.ValueString(),
+		crv: model.Crv.ValueString(),
+	})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := generateECJWK(model.KID.ValueString(), model.Use.ValueString(), model.Alg.ValueString(), model.Crv.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("EC Key Generation Failed", err.Error())
+		return
+	}
+
+	if err := r.providerData.keyDefaults().setComputedKID(key, model.KID, model.KIDStrategy); err != nil {
+		resp.Diagnostics.AddError("Key ID Generation Failed", err.Error())
+		return
+	}
+	model.KID = types.StringValue(key.KeyID())
+
+	keyJSON, publicJSON, thumbprint, err := ephemeralKeyValues(key)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create EC key", err.Error())
+		return
+	}
+	model.KeyJSON = types.StringValue(keyJSON)
+	model.PublicKeyJSON = types.StringValue(publicJSON)
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	privatePEM, pkcs8PEM, publicPEM, err := keyPEMs(key, pemFormatSEC1)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode EC key as PEM", err.Error())
+		return
+	}
+	model.PrivateKeyPEM = types.StringValue(privatePEM)
+	model.PrivateKeyPEMPKCS8 = types.StringValue(pkcs8PEM)
+	model.PublicKeyPEM = types.StringValue(publicPEM)
+
+	diags = resp.Result.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ValidateConfig applies the same rules as the jwk_ec_key resource
+func (r *jwkECKeyEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var model jwkECKeyEphemeralModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Attributes not given may be set from the defaults of the provider, and are checked in Open
+	if model.Use.IsNull() || model.Alg.IsNull() || model.Crv.IsNull() {
+		resp.Diagnostics.Append(validateKIDConfig(model.KID, model.KIDStrategy)...)
+		return
+	}
+
+	resp.Diagnostics.Append(validateECKeyConfig(jwkECKeyModel{
+		KID:         model.KID,
+		KIDStrategy: model.KIDStrategy,
+		Use:         model.Use,
+		Crv:         model.Crv,
+		Alg:         model.Alg,
+	})...)
+}
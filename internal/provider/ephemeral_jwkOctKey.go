@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Creates a new instance of the jwkOctKeyEphemeralResource.
+func NewJwkOctKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &jwkOctKeyEphemeralResource{}
+}
+
+// jwkOctKeyEphemeralResource generates a symmetric key on every run without storing it in state.
+type jwkOctKeyEphemeralResource struct {
+	providerData *jwkProviderData
+}
+
+// This struct gets populated with the configuration values
+type jwkOctKeyEphemeralModel struct {
+	KID         types.String `tfsdk:"kid"`
+	KIDStrategy types.String `tfsdk:"kid_strategy"`
+	Use         types.String `tfsdk:"use"`
+	Alg         types.String `tfsdk:"alg"`
+	Size        types.Int64  `tfsdk:"size"`
+	OctKeyJSON  types.String `tfsdk:"json"`
+	Thumbprint  types.String `tfsdk:"thumbprint"`
+}
+
+// Ephemeral Resource Documentation
+func (r *jwkOctKeyEphemeralResource) Documentation() string {
+	return `This ephemeral resource generates a symmetric (oct) key for JSON Web Key (JWK) purposes on every run.
+The key is never stored in state or plan, so it can be handed to write-only attributes, e.g. of a secrets manager.
+Persist only the 'thumbprint' in regular resources. Requires Terraform 1.10 or later.`
+}
+
+// Ephemeral Resource Metadata
+func (r *jwkOctKeyEphemeralResource) Metadata(_ context.Context, _ ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = "jwk_oct_key"
+}
+
+// Ephemeral Resource Configure
+func (r *jwkOctKeyEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	r.providerData = resourceProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+// Ephemeral Resource Schema
+func (r *jwkOctKeyEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	sigAlgs := keys(OCTSignatureAlgorithms)
+	encAlgs := keys(OCTSEncryptionAlgorithms)
+
+	resp.Schema = schema.Schema{
+		Description: r.Documentation(),
+
+		Attributes: map[string]schema.Attribute{
+			"kid": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "The Key ID (KID) is a unique identifier for the key. It is used to distinguish different keys in a key set. " +
+					"If not given, the Key ID is generated according to `kid_strategy`.",
+			},
+			"kid_strategy": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"Strategy used to generate the Key ID, when `kid` is not given. `%s`. Defaults to `%s`.",
+					strings.Join(validKIDStrategies, "`, `"), defaultKIDStrategy,
+				),
+				Validators: []validator.String{stringOneOf(validKIDStrategies...)},
+			},
+			"use": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Specifies the intended use of the key. Allowed values: `sig` (for signing) and `enc` (for encryption). " +
+					"Required, unless `use` is given in `defaults` of the provider.",
+				Validators: []validator.String{stringOneOf(validUses...)},
+			},
+			"alg": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: fmt.Sprintf(
+					"The cryptographic algorithm associated with the key. `%s` for signing, `%s` for encryption. "+
+						"Defaults to the algorithm for `oct` in `defaults` of the provider, when it suits `use`.",
+					strings.Join(sigAlgs, "`, `"), strings.Join(encAlgs, "`, `"),
+				),
+			},
+			"size": schema.Int64Attribute{
+				Required:    true,
+				Description: "The size of the key in bits. The size needs to be divisible by 8.",
+				Validators:  []validator.Int64{int64MultipleOf(8)},
+			},
+			"json": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The JSON representation of the key in JWK (JSON Web Key) format.",
+			},
+			"thumbprint": schema.StringAttribute{
+				Computed:    true,
+				Description: "The RFC 7638 JWK Thumbprint of the key (base64url encoded SHA-256).",
+			},
+		},
+	}
+}
+
+// Open generates the key
+func (r *jwkOctKeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var model jwkOctKeyEphemeralModel
+
+	diags := req.Config.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config := model
+	defaults := r.providerData.keyDefaults()
+	model.Use = defaultString(config.Use, model.Use, defaults.useValue())
+	model.Alg = defaultString(config.Alg, model.Alg, defaults.algValue("oct", model.Use))
+
+	if model.Use.IsNull() {
+		addMissingDefaultError(&resp.Diagnostics, "use", "use")
+		return
+	}
+
+	// Checks skipped in ValidateConfig, as the values were not known without the defaults
+	if config.Use.IsNull() || config.Alg.IsNull() {
+		resp.Diagnostics.Append(validateOctKeyConfig(jwkOctKeyModel{
+			KID:         model.KID,
+			KIDStrategy: model.KIDStrategy,
+			Use:         model.Use,
+			Alg:         model.Alg,
+			Size:        model.Size,
+		})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	num_bytes := int(model.Size.ValueInt64()) / 8 // Number of bytes
+	// Policy of the provider applies to ephemeral keys as well
+	resp.Diagnostics.Append(r.providerData.keyPolicy().checkKey(policyKey{
+		kty:  "oct",
+		use:  model.Use.ValueString(),
+		alg:  model.Alg.ValueString(),
+		size: model.Size.ValueInt64(),
+	})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := generateOctJWK(model.KID.ValueString(), model.Use.ValueString(),
+		model.Alg.ValueString(), num_bytes)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Symmetric Key Generation Failed", err.Error())
+		return
+	}
+
+	if err := r.providerData.keyDefaults().setComputedKID(key, model.KID, model.KIDStrategy); err != nil {
+		resp.Diagnostics.AddError("Key ID Generation Failed", err.Error())
+		return
+	}
+	model.KID = types.StringValue(key.KeyID())
+
+	keyJSON, _, thumbprint, err := ephemeralKeyValues(key)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create symmetric key", err.Error())
+		return
+	}
+	model.OctKeyJSON = types.StringValue(keyJSON)
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	diags = resp.Result.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ValidateConfig applies the same rules as the jwk_oct_key resource
+func (r *jwkOctKeyEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var model jwkOctKeyEphemeralModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Attributes not given may be set from the defaults of the provider, and are checked in Open
+	if model.Use.IsNull() || model.Alg.IsNull() {
+		resp.Diagnostics.Append(validateKIDConfig(model.KID, model.KIDStrategy)...)
+		return
+	}
+
+	resp.Diagnostics.Append(validateOctKeyConfig(jwkOctKeyModel{
+		KID:         model.KID,
+		KIDStrategy: model.KIDStrategy,
+		Use:         model.Use,
+		Alg:         model.Alg,
+		Size:        model.Size,
+	})...)
+}
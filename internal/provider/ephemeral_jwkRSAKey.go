@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Creates a new instance of the jwkRSAKeyEphemeralResource.
+func NewJwkRSAKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &jwkRSAKeyEphemeralResource{}
+}
+
+// jwkRSAKeyEphemeralResource generates an RSA key on every run without storing it in state.
+type jwkRSAKeyEphemeralResource struct {
+	providerData *jwkProviderData
+}
+
+// This struct gets populated with the configuration values
+type jwkRSAKeyEphemeralModel struct {
+	KID                types.String `tfsdk:"kid"`
+	KIDStrategy        types.String `tfsdk:"kid_strategy"`
+	Use                types.String `tfsdk:"use"`
+	Alg                types.String `tfsdk:"alg"`
+	Size               types.Int64  `tfsdk:"size"`
+	KeyJSON            types.String `tfsdk:"json"`
+	PublicKeyJSON      types.String `tfsdk:"public_json"`
+	Thumbprint         types.String `tfsdk:"thumbprint"`
+	PrivateKeyPEM      types.String `tfsdk:"private_key_pem"`
+	PrivateKeyPEMPKCS8 types.String `tfsdk:"private_key_pem_pkcs8"`
+	PublicKeyPEM       types.String `tfsdk:"public_key_pem"`
+}
+
+// Ephemeral Resource Documentation
+func (r *jwkRSAKeyEphemeralResource) Documentation() string {
+	return `This ephemeral resource generates an RSA key for JSON Web Key (JWK) purposes on every run.
+The key is never stored in state or plan, so it can be handed to write-only attributes, e.g. of a secrets manager.
+Persist only the public key ('public_json') or the 'thumbprint' in regular resources. Requires Terraform 1.10 or later.`
+}
+
+// Ephemeral Resource Metadata
+func (r *jwkRSAKeyEphemeralResource) Metadata(_ context.Context, _ ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = "jwk_rsa_key"
+}
+
+// Ephemeral Resource Configure
+func (r *jwkRSAKeyEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	r.providerData = resourceProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+// Ephemeral Resource Schema
+func (r *jwkRSAKeyEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	sigAlgs := keys(RSASignatureAlgorithms)
+	encAlgs := keys(RSAEncryptionAlgorithms)
+
+	resp.Schema = schema.Schema{
+		Description: r.Documentation(),
+
+		Attributes: map[string]schema.Attribute{
+			"kid": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "The Key ID (KID) is a unique identifier for the key. It is used to distinguish different keys in a key set. " +
+					"If not given, the Key ID is generated according to `kid_strategy`.",
+			},
+			"kid_strategy": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"Strategy used to generate the Key ID, when `kid` is not given. `%s`. Defaults to `%s`.",
+					strings.Join(validKIDStrategies, "`, `"), defaultKIDStrategy,
+				),
+				Validators: []validator.String{stringOneOf(validKIDStrategies...)},
+			},
+			"use": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Specifies the intended use of the key. Allowed values: `sig` (for signing) and `enc` (for encryption). " +
+					"Required, unless `use` is given in `defaults` of the provider.",
+				Validators: []validator.String{stringOneOf(validUses...)},
+			},
+			"alg": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: fmt.Sprintf(
+					"The cryptographic algorithm associated with the key. `%s` for signing, `%s` for encryption. "+
+						"Defaults to the algorithm for `RSA` in `defaults` of the provider, when it suits `use`.",
+					strings.Join(sigAlgs, "`, `"), strings.Join(encAlgs, "`, `"),
+				),
+			},
+			"size": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Description: "The size of the key in bits. For RSA keys, common values are 2048, 3072, or 4096. " +
+					"Required, unless `rsa_size` is given in `defaults` of the provider.",
+				Validators: []validator.Int64{int64AtLeast(2048)},
+			},
+			"json": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The JSON representation of the key in JWK (JSON Web Key) format.",
+			},
+			"public_json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The JSON representation of the public key in JWK format.",
+			},
+			"thumbprint": schema.StringAttribute{
+				Computed:    true,
+				Description: "The RFC 7638 JWK Thumbprint of the key (base64url encoded SHA-256).",
+			},
+			"private_key_pem": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The private key in PEM format, PKCS#1 (`RSA PRIVATE KEY`).",
+			},
+			"private_key_pem_pkcs8": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The private key in PEM format, PKCS#8 (`PRIVATE KEY`).",
+			},
+			"public_key_pem": schema.StringAttribute{
+				Computed:    true,
+				Description: "The public key in PEM format, PKIX SubjectPublicKeyInfo (`PUBLIC KEY`).",
+			},
+		},
+	}
+}
+
+// Open generates the key
+func (r *jwkRSAKeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var model jwkRSAKeyEphemeralModel
+
+	diags := req.Config.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config := model
+	defaults := r.providerData.keyDefaults()
+	model.Use = defaultString(config.Use, model.Use, defaults.useValue())
+	model.Alg = defaultString(config.Alg, model.Alg, defaults.algValue("RSA", model.Use))
+	model.Size = defaultInt64(config.Size, model.Size, defaults.rsaSizeValue())
+
+	if model.Use.IsNull() {
+		addMissingDefaultError(&resp.Diagnostics, "use", "use")
+	}
+	if model.Size.IsNull() {
+		addMissingDefaultError(&resp.Diagnostics, "size", "rsa_size")
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checks skipped in ValidateConfig, as the values were not known without the defaults
+	if config.Use.IsNull() || config.Alg.IsNull() || config.Size.IsNull() {
+		resp.Diagnostics.Append(validateRSAKeyConfig(jwkRSAKeyModel{
+			KID:         model.KID,
+			KIDStrategy: model.KIDStrategy,
+			Use:         model.Use,
+			Alg:         model.Alg,
+			Size:        model.Size,
+		})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Policy of the provider applies to ephemeral keys as well
+	resp.Diagnostics.Append(r.providerData.keyPolicy().checkKey(policyKey{
+		kty:  "RSA",
+		use:  model.Use.ValueString(),
+		alg:  model.Alg.ValueString(),
+		size: model.Size.ValueInt64(),
+	})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := generateRSAJWK(model.KID.ValueString(), model.Use.ValueString(), model.Alg.ValueString(), int(model.Size.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError("RSA Key Generation Failed", err.Error())
+		return
+	}
+
+	if err := r.providerData.keyDefaults().setComputedKID(key, model.KID, model.KIDStrategy); err != nil {
+		resp.Diagnostics.AddError("Key ID Generation Failed", err.Error())
+		return
+	}
+	model.KID = types.StringValue(key.KeyID())
+
+	keyJSON, publicJSON, thumbprint, err := ephemeralKeyValues(key)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create RSA key", err.Error())
+		return
+	}
+	model.KeyJSON = types.StringValue(keyJSON)
+	model.PublicKeyJSON = types.StringValue(publicJSON)
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	privatePEM, pkcs8PEM, publicPEM, err := keyPEMs(key, pemFormatPKCS1)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode RSA key as PEM", err.Error())
+		return
+	}
+	model.PrivateKeyPEM = types.StringValue(privatePEM)
+	model.PrivateKeyPEMPKCS8 = types.StringValue(pkcs8PEM)
+	model.PublicKeyPEM = types.StringValue(publicPEM)
+
+	diags = resp.Result.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ValidateConfig applies the same rules as the jwk_rsa_key resource
+func (r *jwkRSAKeyEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var model jwkRSAKeyEphemeralModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Attributes not given may be set from the defaults of the provider, and are checked in Open
+	if model.Use.IsNull() || model.Alg.IsNull() || model.Size.IsNull() {
+		resp.Diagnostics.Append(validateKIDConfig(model.KID, model.KIDStrategy)...)
+		return
+	}
+
+	resp.Diagnostics.Append(validateRSAKeyConfig(jwkRSAKeyModel{
+		KID:         model.KID,
+		KIDStrategy: model.KIDStrategy,
+		Use:         model.Use,
+		Alg:         model.Alg,
+		Size:        model.Size,
+	})...)
+}
@@ -0,0 +1,18 @@
+//go:build jwx_es256k
+// +build jwx_es256k
+
+package provider
+
+import (
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// secp256k1 (ES256K, RFC 8812) support. The curve is only available, when
+// the provider is built with the same 'jwx_es256k' tag, which enables it in jwx.
+func init() {
+	ellipticCurves["secp256k1"] = secp256k1.S256()
+	validECCurves = append(validECCurves, "secp256k1")
+
+	ECSigningAlgorithmsToCurves["ES256K"] = "secp256k1"
+	ECSigAlgorithms["ES256K"] = 256
+}
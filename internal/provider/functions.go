@@ -7,7 +7,9 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 )
 
@@ -74,3 +76,678 @@ func (f *publicKeyFunction) Run(ctx context.Context, req function.RunRequest, re
 	// Return the public key as a string
 	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, string(publicJWKBytes)))
 }
+
+type thumbprintFunction struct{}
+
+func NewThumbprintFunction() function.Function {
+	return &thumbprintFunction{}
+}
+
+func (r thumbprintFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "thumbprint"
+}
+
+func (r thumbprintFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Computes JWK Thumbprint",
+		Description: "Computes the RFC 7638 JWK Thumbprint of a key given in Json format of JWK. Returns the base64url encoded thumbprint. " +
+			"With hash prefixed by `urn:`, e.g. `urn:SHA-256`, returns an RFC 9278 JWK Thumbprint URI, " +
+			"such as `urn:ietf:params:oauth:jwk-thumbprint:sha-256:NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs`. " +
+			"Public and private keys have the same thumbprint.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "jwk",
+				Description: "key in json",
+			},
+			function.StringParameter{
+				Name:        "hash",
+				Description: "Hash function: `SHA-1`, `SHA-256` or `SHA-512`. `SHA-256` and `SHA-512` may be prefixed with `urn:`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *thumbprintFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var jwkStr string
+	var hash string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &jwkStr, &hash))
+
+	if resp.Error != nil {
+		return
+	}
+
+	key, err := json2jwk(jwkStr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to convert key to JWK: "+err.Error())
+		return
+	}
+
+	thumbprint, err := jwkThumbprint(key, hash)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, thumbprint))
+}
+
+type parseJWKFunction struct{}
+
+func NewParseJWKFunction() function.Function {
+	return &parseJWKFunction{}
+}
+
+func (r parseJWKFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_jwk"
+}
+
+func (r parseJWKFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses a JWK",
+		Description: "Parses and validates a key given in Json format of JWK. Returns an object with `kty`, `kid`, `use`, `alg`, `crv`, " +
+			"the key `size` in bits, `is_private`, `key_ops`, the RFC 7638 `thumbprint` (SHA-256) and the `public_json` of the key, " +
+			"as the `jwk_key_info` data source. Parameters not given in the key are empty.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "jwk",
+				Description: "key in json",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: keyInfoAttributeTypes,
+		},
+	}
+}
+
+func (f *parseJWKFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var jwkStr string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &jwkStr))
+
+	if resp.Error != nil {
+		return
+	}
+
+	info, err := parseKeyInfo(ctx, jwkStr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to parse JWK: "+err.Error())
+		return
+	}
+
+	result, diags := info.objectValue(ctx)
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+type pemToJWKFunction struct{}
+
+func NewPemToJWKFunction() function.Function {
+	return &pemToJWKFunction{}
+}
+
+func (r pemToJWKFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "pem_to_jwk"
+}
+
+func (r pemToJWKFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts PEM to JWK",
+		Description: "Converts a PEM encoded key to Json format of JWK. Supported PEM blocks are PKCS#1 (`RSA PRIVATE KEY`, `RSA PUBLIC KEY`), " +
+			"SEC 1 (`EC PRIVATE KEY`), PKCS#8 (`PRIVATE KEY`), SPKI (`PUBLIC KEY`) and X.509 certificates (`CERTIFICATE`). " +
+			"The public key of a certificate is returned with the certificate in `x5c`. " +
+			"If kid is empty, the RFC 7638 JWK Thumbprint of the key is used as Key ID. " +
+			"Fails, when the key violates the policy given in the `JWK_POLICY` environment variable. The `policy` of the provider configuration does not apply to functions, so without `JWK_POLICY` the function is not restricted by any policy.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "pem",
+				Description: "PEM encoded key or certificate",
+			},
+			function.StringParameter{
+				Name:        "kid",
+				Description: "Key ID of the key, or empty",
+			},
+			function.StringParameter{
+				Name:        "use",
+				Description: "Intended use of the key, `sig`, `enc` or empty",
+			},
+			function.StringParameter{
+				Name:        "alg",
+				Description: "Algorithm of the key, or empty",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *pemToJWKFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var pemStr, kid, use, alg string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &pemStr, &kid, &use, &alg))
+
+	if resp.Error != nil {
+		return
+	}
+
+	policy, err := functionPolicy()
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Invalid policy: " + err.Error()}
+		return
+	}
+
+	if use != "" && !isValid(use, validUses) {
+		resp.Error = &function.FuncError{Text: "Invalid use '" + use + "', expected 'sig', 'enc' or empty"}
+		return
+	}
+
+	key, err := pemToJWK(pemStr)
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Failed to convert PEM to JWK: " + err.Error()}
+		return
+	}
+
+	if kid == "" {
+		kid, err = generateKID(key, defaultKIDStrategy)
+		if err != nil {
+			resp.Error = &function.FuncError{Text: "Failed to generate Key ID: " + err.Error()}
+			return
+		}
+	}
+	key.Set(jwk.KeyIDKey, kid)
+
+	if use != "" {
+		key.Set(jwk.KeyUsageKey, use)
+	}
+	if alg != "" {
+		key.Set(jwk.AlgorithmKey, alg)
+	}
+
+	if err := policy.checkJWK(key, ""); err != nil {
+		resp.Error = &function.FuncError{Text: "Policy violation: " + err.Error()}
+		return
+	}
+
+	keyBytes, err := json.Marshal(key)
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Failed to serialize key to JSON: " + err.Error()}
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, string(keyBytes)))
+}
+
+type jwkToPEMFunction struct{}
+
+func NewJwkToPEMFunction() function.Function {
+	return &jwkToPEMFunction{}
+}
+
+func (r jwkToPEMFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "jwk_to_pem"
+}
+
+func (r jwkToPEMFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts JWK to PEM",
+		Description: "Converts a key given in Json format of JWK to PEM. Private keys can be encoded in `pkcs1` (RSA), `sec1` (EC) " +
+			"and `pkcs8` formats. The `spki` format encodes the public key of both private and public keys. " +
+			"Symmetric keys cannot be encoded in PEM.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "jwk",
+				Description: "key in json",
+			},
+			function.StringParameter{
+				Name:        "format",
+				Description: "PEM format: `pkcs1`, `sec1`, `pkcs8` or `spki`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *jwkToPEMFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var jwkStr, format string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &jwkStr, &format))
+
+	if resp.Error != nil {
+		return
+	}
+
+	key, err := json2jwk(jwkStr)
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Failed convert key to JWK:" + err.Error()}
+		return
+	}
+
+	pemStr, err := jwkToPEM(key, format)
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Failed to convert JWK to PEM: " + err.Error()}
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, pemStr))
+}
+
+type signJWTFunction struct{}
+
+func NewSignJWTFunction() function.Function {
+	return &signJWTFunction{}
+}
+
+func (r signJWTFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "sign_jwt"
+}
+
+func (r signJWTFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Signs a JWT",
+		Description: "Signs claims with a private key given in Json format of JWK, and returns a compact JWS (JWT). " +
+			"The algorithm is taken from `alg` of the key, unless given in headers, and `kid` from the key. " +
+			"Claims `iat`, `exp` and `nbf` can be given relative to the signing time: `now`, `now+<duration>` or `now-<duration>`, " +
+			"e.g. `now+90d` or `now-5m`. Keys with use `enc` are rejected. " +
+			"The key and the algorithm must comply with the policy given in the `JWK_POLICY` environment variable. The `policy` of the provider configuration does not apply to functions, so without `JWK_POLICY` the function is not restricted by any policy. " +
+			"The result changes on every run, so store it e.g. with `terraform_data` when it should be stable.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "private_jwk",
+				Description: "private key in json",
+			},
+			function.StringParameter{
+				Name:        "claims",
+				Description: "claims as a Json object, e.g. `jsonencode({sub = \"service\", exp = \"now+1h\"})`",
+			},
+			function.StringParameter{
+				Name:        "headers",
+				Description: "additional protected headers as a Json object, or empty. Default `typ` is `JWT`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *signJWTFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var privateJWKStr, claimsStr, headersStr string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &privateJWKStr, &claimsStr, &headersStr))
+
+	if resp.Error != nil {
+		return
+	}
+
+	policy, err := functionPolicy()
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Invalid policy: " + err.Error()}
+		return
+	}
+
+	privateJWK, err := json2jwk(privateJWKStr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to convert private key to JWK: "+err.Error())
+		return
+	}
+
+	claims, err := parseJSONObject(claimsStr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, "Invalid claims: "+err.Error())
+		return
+	}
+
+	headers, err := parseJSONObject(headersStr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(2, "Invalid headers: "+err.Error())
+		return
+	}
+
+	// Algorithm of the headers overrides the one of the key
+	alg, _ := headers["alg"].(string)
+	if err := policy.checkJWK(privateJWK, alg); err != nil {
+		resp.Error = &function.FuncError{Text: "Policy violation: " + err.Error()}
+		return
+	}
+
+	token, err := signJWT(privateJWK, claims, headers)
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Failed to sign JWT: " + err.Error()}
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, token))
+}
+
+// Result of verify_jws and decode_jwt functions
+var jwsPartsAttributeTypes = map[string]attr.Type{
+	"header":  types.StringType,
+	"payload": types.StringType,
+}
+
+type verifyJWSFunction struct{}
+
+func NewVerifyJWSFunction() function.Function {
+	return &verifyJWSFunction{}
+}
+
+func (r verifyJWSFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "verify_jws"
+}
+
+func (r verifyJWSFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Verifies a JWS",
+		Description: "Verifies the signature of a JWS, such as a JWT, with the keys of a JWK key set. The key is selected by `kid` " +
+			"of the token, or the only key of the set is used, when the token has no `kid`. Keys with use `enc` are not used. " +
+			"Returns an object with the protected `header` and the `payload` as Json strings. Claims, such as `exp`, are not validated. " +
+			"Fails, when the signature cannot be verified, or the key or the algorithm of the token violate the policy given in the `JWK_POLICY` environment variable. The `policy` of the provider configuration does not apply to functions, so without `JWK_POLICY` the function is not restricted by any policy.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "jwks_json",
+				Description: "key set in json, or a single key",
+			},
+			function.StringParameter{
+				Name:        "token",
+				Description: "JWS in compact or JSON serialization",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: jwsPartsAttributeTypes,
+		},
+	}
+}
+
+func (f *verifyJWSFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var jwksStr, token string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &jwksStr, &token))
+
+	if resp.Error != nil {
+		return
+	}
+
+	policy, err := functionPolicy()
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Invalid policy: " + err.Error()}
+		return
+	}
+
+	keyset, err := jwk.Parse([]byte(jwksStr))
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to parse key set: "+err.Error())
+		return
+	}
+
+	header, payload, err := verifyJWS(keyset, token)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, "Failed to verify JWS: "+err.Error())
+		return
+	}
+
+	if err := checkJWSPolicy(policy, keyset, header); err != nil {
+		resp.Error = &function.FuncError{Text: "Policy violation: " + err.Error()}
+		return
+	}
+
+	result, diags := types.ObjectValue(jwsPartsAttributeTypes, map[string]attr.Value{
+		"header":  types.StringValue(header),
+		"payload": types.StringValue(payload),
+	})
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+type decodeJWTFunction struct{}
+
+func NewDecodeJWTFunction() function.Function {
+	return &decodeJWTFunction{}
+}
+
+func (r decodeJWTFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "decode_jwt"
+}
+
+func (r decodeJWTFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decodes a JWT",
+		Description: "Decodes a JWT in compact serialization without verifying its signature. " +
+			"Returns an object with the protected `header` and the claims as `payload`, both as Json strings. " +
+			"Use `verify_jws` to check, that the token is genuine.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "token",
+				Description: "JWT in compact serialization",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: jwsPartsAttributeTypes,
+		},
+	}
+}
+
+func (f *decodeJWTFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var token string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &token))
+
+	if resp.Error != nil {
+		return
+	}
+
+	header, claims, err := decodeJWT(token)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to decode JWT: "+err.Error())
+		return
+	}
+
+	result, diags := types.ObjectValue(jwsPartsAttributeTypes, map[string]attr.Value{
+		"header":  types.StringValue(header),
+		"payload": types.StringValue(claims),
+	})
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+type encryptJWEFunction struct{}
+
+func NewEncryptJWEFunction() function.Function {
+	return &encryptJWEFunction{}
+}
+
+func (r encryptJWEFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "encrypt_jwe"
+}
+
+func (r encryptJWEFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Encrypts to a JWE",
+		Description: "Encrypts plaintext to a key given in Json format of JWK, and returns a JWE. The key management algorithm is taken " +
+			"from `alg` of the key: `RSA1_5`, `RSA-OAEP`, `RSA-OAEP-256`, `ECDH-ES`, `ECDH-ES+A128KW`, `ECDH-ES+A192KW`, `ECDH-ES+A256KW`, " +
+			"`A128KW`, `A192KW`, `A256KW`, `A128GCMKW`, `A192GCMKW`, `A256GCMKW`, `PBES2-HS256+A128KW`, `PBES2-HS384+A192KW`, " +
+			"`PBES2-HS512+A256KW` or `dir`. With `dir`, the size of the key must match the content encryption. " +
+			"The `kid` of the key is included in the header. Keys with use `sig` are rejected, as well as keys violating the policy given in the `JWK_POLICY` environment variable. The `policy` of the provider configuration does not apply to functions, so without `JWK_POLICY` the function is not restricted by any policy. " +
+			"The result changes on every run, as the content encryption key is random.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "public_jwk",
+				Description: "public key in json, or a symmetric key",
+			},
+			function.StringParameter{
+				Name:        "plaintext",
+				Description: "data to encrypt",
+			},
+			function.StringParameter{
+				Name:        "enc",
+				Description: "Content encryption: `A128GCM`, `A192GCM`, `A256GCM`, `A128CBC-HS256`, `A192CBC-HS384` or `A256CBC-HS512`. Defaults to `A256GCM`, when empty",
+			},
+			function.StringParameter{
+				Name:        "serialization",
+				Description: "`compact` or `json`. Defaults to `compact`, when empty",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *encryptJWEFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var publicJWKStr, plaintext, enc, serialization string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &publicJWKStr, &plaintext, &enc, &serialization))
+
+	if resp.Error != nil {
+		return
+	}
+
+	policy, err := functionPolicy()
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Invalid policy: " + err.Error()}
+		return
+	}
+
+	publicJWK, err := json2jwk(publicJWKStr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to convert public key to JWK: "+err.Error())
+		return
+	}
+
+	if err := policy.checkJWK(publicJWK, ""); err != nil {
+		resp.Error = &function.FuncError{Text: "Policy violation: " + err.Error()}
+		return
+	}
+
+	encrypted, err := encryptJWE(publicJWK, plaintext, enc, serialization)
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Failed to encrypt JWE: " + err.Error()}
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, encrypted))
+}
+
+type decryptJWEFunction struct{}
+
+func NewDecryptJWEFunction() function.Function {
+	return &decryptJWEFunction{}
+}
+
+func (r decryptJWEFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "decrypt_jwe"
+}
+
+func (r decryptJWEFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decrypts a JWE",
+		Description: "Decrypts a JWE in compact or JSON serialization with a private key given in Json format of JWK, " +
+			"using the key management algorithm of the key. Returns the plaintext. The key must comply with the policy given in the `JWK_POLICY` environment variable. The `policy` of the provider configuration does not apply to functions, so without `JWK_POLICY` the function is not restricted by any policy.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "private_jwk",
+				Description: "private key in json, or a symmetric key",
+			},
+			function.StringParameter{
+				Name:        "jwe",
+				Description: "JWE in compact or JSON serialization",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *decryptJWEFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var privateJWKStr, encrypted string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &privateJWKStr, &encrypted))
+
+	if resp.Error != nil {
+		return
+	}
+
+	policy, err := functionPolicy()
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Invalid policy: " + err.Error()}
+		return
+	}
+
+	privateJWK, err := json2jwk(privateJWKStr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Failed to convert private key to JWK: "+err.Error())
+		return
+	}
+
+	if err := policy.checkJWK(privateJWK, ""); err != nil {
+		resp.Error = &function.FuncError{Text: "Policy violation: " + err.Error()}
+		return
+	}
+
+	plaintext, err := decryptJWE(privateJWK, encrypted)
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Failed to decrypt JWE: " + err.Error()}
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, plaintext))
+}
+
+type decryptStateFunction struct{}
+
+func NewDecryptStateFunction() function.Function {
+	return &decryptStateFunction{}
+}
+
+func (r decryptStateFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "decrypt_state"
+}
+
+func (r decryptStateFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decrypts a key encrypted in state",
+		Description: "Decrypts a value encrypted by `state_encryption` of the provider, e.g. `json` of a key resource, " +
+			"with the same passphrase or key encryption key. Plaintext values are returned as is.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "value",
+				Description: "encrypted value, a JWE in compact serialization",
+			},
+			function.StringParameter{
+				Name:        "secret",
+				Description: "passphrase, or key encryption key in json, as given in state_encryption",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *decryptStateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value, secret string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value, &secret))
+
+	if resp.Error != nil {
+		return
+	}
+
+	encryption, err := stateEncryptionFromSecret(secret)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, "Invalid secret: "+err.Error())
+		return
+	}
+
+	plaintext, err := encryption.open(value)
+	if err != nil {
+		resp.Error = &function.FuncError{Text: "Failed to decrypt state value: " + err.Error()}
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, plaintext))
+}
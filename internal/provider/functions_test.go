@@ -0,0 +1,440 @@
+package provider_test
+
+import (
+	"encoding/base64"
+	"os"
+	"regexp"
+	"testing"
+
+	"terraform-provider-jwk/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// Public key of RFC 7638, section 3.1
+const rfc7638Key = `{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}`
+
+func TestThumbprintFunction(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+locals {
+  key = <<EOT
+` + rfc7638Key + `
+EOT
+}
+
+output "sha256" {
+  value = provider::jwk::thumbprint(local.key, "SHA-256")
+}
+
+output "uri" {
+  value = provider::jwk::thumbprint(local.key, "urn:SHA-256")
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("sha256", "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"),
+					resource.TestCheckOutput("uri", "urn:ietf:params:oauth:jwk-thumbprint:sha-256:NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"),
+				),
+			},
+			{
+				Config: `
+resource "jwk_rsa_key" "example" {
+  kid  = "rsa-1"
+  use  = "sig"
+  size = 2048
+  alg  = "RS256"
+}
+
+# Public key has the same thumbprint as the private key
+output "same_thumbprint" {
+  value = provider::jwk::thumbprint(provider::jwk::public_key(jwk_rsa_key.example.json, ""), "SHA-256") == jwk_rsa_key.example.thumbprint
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("jwk_rsa_key.example", "thumbprint", regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)),
+					resource.TestCheckOutput("same_thumbprint", "true"),
+				),
+			},
+			{
+				Config: `
+output "invalid" {
+  value = provider::jwk::thumbprint("{\"kty\":\"oct\",\"k\":\"c2VjcmV0\"}", "MD5")
+}
+`,
+				ExpectError: regexp.MustCompile(`unsupported hash 'MD5'`),
+			},
+			{
+				// SHA-1 is not registered for Named Information, so it has no JWK Thumbprint URI
+				Config: `
+output "invalid" {
+  value = provider::jwk::thumbprint("{\"kty\":\"oct\",\"k\":\"c2VjcmV0\"}", "urn:SHA-1")
+}
+`,
+				ExpectError: regexp.MustCompile(`unsupported hash 'urn:SHA-1' for JWK Thumbprint URI`),
+			},
+		},
+	})
+}
+
+func TestParseJWKFunction(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+locals {
+  info = provider::jwk::parse_jwk(<<EOT
+` + rfc7638Key + `
+EOT
+  )
+}
+
+output "kty" {
+  value = local.info.kty
+}
+
+output "size" {
+  value = local.info.size
+}
+
+output "is_private" {
+  value = local.info.is_private
+}
+
+output "thumbprint" {
+  value = local.info.thumbprint
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("kty", "RSA"),
+					resource.TestCheckOutput("size", "2048"),
+					resource.TestCheckOutput("is_private", "false"),
+					resource.TestCheckOutput("thumbprint", "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"),
+				),
+			},
+			{
+				Config: `
+resource "jwk_okp_key" "example" {
+  kid = "okp-1"
+  use = "sig"
+  crv = "Ed25519"
+}
+
+locals {
+  info = provider::jwk::parse_jwk(jwk_okp_key.example.json)
+}
+
+output "crv" {
+  value = local.info.crv
+}
+
+output "is_private" {
+  value = local.info.is_private
+}
+
+# Public key has the same properties, except it is not private
+output "public_thumbprint" {
+  value = provider::jwk::parse_jwk(local.info.public_json).thumbprint == jwk_okp_key.example.thumbprint
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("crv", "Ed25519"),
+					resource.TestCheckOutput("is_private", "true"),
+					resource.TestCheckOutput("public_thumbprint", "true"),
+				),
+			},
+			{
+				Config: `
+output "info" {
+  value = provider::jwk::parse_jwk(jsonencode({ kty = "RSA", n = "AQAB" }))
+}
+`,
+				ExpectError: regexp.MustCompile(`Failed to parse JWK`),
+			},
+		},
+	})
+}
+
+func TestPEMFunctions(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_ec_key" "example" {
+  kid = "ec-1"
+  use = "sig"
+  crv = "P-256"
+  alg = "ES256"
+}
+
+resource "jwk_okp_key" "example" {
+  kid = "ed-1"
+  use = "sig"
+  crv = "Ed25519"
+}
+
+locals {
+  ec_pkcs8 = provider::jwk::jwk_to_pem(jwk_ec_key.example.json, "pkcs8")
+  ec_from  = provider::jwk::pem_to_jwk(local.ec_pkcs8, "ec-2", "sig", "ES256")
+  ed_spki  = provider::jwk::jwk_to_pem(jwk_okp_key.example.json, "spki")
+  ed_from  = provider::jwk::pem_to_jwk(local.ed_spki, "", "", "")
+}
+
+output "ec_same_key" {
+  value = nonsensitive(provider::jwk::thumbprint(local.ec_from, "SHA-256") == jwk_ec_key.example.thumbprint)
+}
+
+output "ec_kid" {
+  value = nonsensitive(jsondecode(local.ec_from).kid)
+}
+
+output "ec_sec1_matches" {
+  value = nonsensitive(provider::jwk::jwk_to_pem(jwk_ec_key.example.json, "sec1") == jwk_ec_key.example.private_key_pem)
+}
+
+# Key ID of the converted public key is its thumbprint
+output "ed_same_key" {
+  value = nonsensitive(jsondecode(local.ed_from).kid == jwk_okp_key.example.thumbprint)
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("ec_same_key", "true"),
+					resource.TestCheckOutput("ec_kid", "ec-2"),
+					resource.TestCheckOutput("ec_sec1_matches", "true"),
+					resource.TestCheckOutput("ed_same_key", "true"),
+				),
+			},
+			{
+				Config: `
+resource "jwk_oct_key" "example" {
+  kid  = "oct-1"
+  use  = "sig"
+  size = 256
+}
+
+output "invalid" {
+  value = provider::jwk::jwk_to_pem(jwk_oct_key.example.json, "pkcs8")
+}
+`,
+				ExpectError: regexp.MustCompile(`symmetric keys cannot be encoded in PEM`),
+			},
+		},
+	})
+}
+
+func TestSignJWTFunction(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256","kid":"ec-1","typ":"JWT"}`))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_ec_key" "example" {
+  kid = "ec-1"
+  use = "sig"
+  crv = "P-256"
+  alg = "ES256"
+}
+
+output "token" {
+  value = nonsensitive(provider::jwk::sign_jwt(jwk_ec_key.example.json, jsonencode({
+    sub = "service"
+    iat = "now"
+    exp = "now+1h"
+  }), ""))
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchOutput("token", regexp.MustCompile(`^`+header+`\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]{86}$`)),
+				),
+			},
+			{
+				Config: `
+resource "jwk_ec_key" "example" {
+  kid = "ec-enc-1"
+  use = "enc"
+  crv = "P-256"
+  alg = "ECDH-ES"
+}
+
+output "token" {
+  value = nonsensitive(provider::jwk::sign_jwt(jwk_ec_key.example.json, jsonencode({sub = "service"}), ""))
+}
+`,
+				ExpectError: regexp.MustCompile(`cannot be used for signing`),
+			},
+		},
+	})
+}
+
+func TestVerifyJWSFunction(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_ec_key" "example" {
+  kid = "ec-1"
+  use = "sig"
+  crv = "P-256"
+  alg = "ES256"
+}
+
+resource "jwk_okp_key" "example" {
+  kid = "ed-1"
+  use = "sig"
+  crv = "Ed25519"
+}
+
+resource "jwk_keyset" "example" {
+  keys = [jwk_ec_key.example.json, jwk_okp_key.example.json]
+}
+
+locals {
+  token    = provider::jwk::sign_jwt(jwk_ec_key.example.json, jsonencode({sub = "service"}), "")
+  verified = provider::jwk::verify_jws(jwk_keyset.example.public_json, local.token)
+  decoded  = provider::jwk::decode_jwt(local.token)
+}
+
+output "kid" {
+  value = nonsensitive(jsondecode(local.verified.header).kid)
+}
+
+output "sub" {
+  value = nonsensitive(jsondecode(local.verified.payload).sub)
+}
+
+output "decoded_sub" {
+  value = nonsensitive(jsondecode(local.decoded.payload).sub)
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("kid", "ec-1"),
+					resource.TestCheckOutput("sub", "service"),
+					resource.TestCheckOutput("decoded_sub", "service"),
+				),
+			},
+			{
+				Config: `
+resource "jwk_ec_key" "example" {
+  kid = "ec-1"
+  use = "sig"
+  crv = "P-256"
+  alg = "ES256"
+}
+
+resource "jwk_ec_key" "other" {
+  kid = "ec-1"
+  use = "sig"
+  crv = "P-256"
+  alg = "ES256"
+}
+
+# Token signed with another key of the same Key ID
+output "verified" {
+  value = nonsensitive(provider::jwk::verify_jws(
+    provider::jwk::public_key(jwk_ec_key.example.json, ""),
+    provider::jwk::sign_jwt(jwk_ec_key.other.json, jsonencode({sub = "service"}), "")
+  ).payload)
+}
+`,
+				ExpectError: regexp.MustCompile(`Failed to verify JWS`),
+			},
+		},
+	})
+}
+
+func TestJWEFunctions(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_rsa_key" "example" {
+  kid  = "rsa-enc-1"
+  use  = "enc"
+  size = 2048
+  alg  = "RSA-OAEP-256"
+}
+
+resource "jwk_oct_key" "example" {
+  kid  = "dir-1"
+  use  = "enc"
+  size = 256
+  alg  = "dir"
+}
+
+locals {
+  rsa_jwe = provider::jwk::encrypt_jwe(provider::jwk::public_key(jwk_rsa_key.example.json, ""), "secret", "", "")
+  dir_jwe = provider::jwk::encrypt_jwe(jwk_oct_key.example.json, "secret", "A256GCM", "json")
+}
+
+output "rsa_plaintext" {
+  value = nonsensitive(provider::jwk::decrypt_jwe(jwk_rsa_key.example.json, local.rsa_jwe))
+}
+
+output "dir_plaintext" {
+  value = nonsensitive(provider::jwk::decrypt_jwe(jwk_oct_key.example.json, local.dir_jwe))
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("rsa_plaintext", "secret"),
+					resource.TestCheckOutput("dir_plaintext", "secret"),
+				),
+			},
+			{
+				Config: `
+resource "jwk_ec_key" "example" {
+  kid = "ec-sig-1"
+  use = "sig"
+  crv = "P-256"
+  alg = "ES256"
+}
+
+output "jwe" {
+  value = provider::jwk::encrypt_jwe(jwk_ec_key.example.json, "secret", "", "")
+  sensitive = true
+}
+`,
+				ExpectError: regexp.MustCompile(`cannot be used for encryption`),
+			},
+		},
+	})
+}
@@ -1,16 +1,37 @@
 package provider
 
 import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"sort"
-
+	"strings"
+	"time"
+
+	"github.com/cloudflare/circl/dh/x448"
+	"github.com/cloudflare/circl/sign/ed448"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/cert"
+	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/x25519"
 )
 
 // isValid checks if a given value is in the list of valid values.
@@ -23,8 +44,8 @@ func isValid(value string, validValues []string) bool {
 	return false
 }
 
-// Gets keys of the [string]int map
-func keys(m map[string]int) []string {
+// Gets the sorted keys of the map
+func keys[V any](m map[string]V) []string {
 	keys := make([]string, len(m))
 	i := 0
 	for k := range m {
@@ -73,6 +94,78 @@ func createJWKKeyset(keys types.List) (string, error) {
 	return string(result), nil
 }
 
+// Create JWK Keyset containing public keys of the given keys.
+// Private members are removed from asymmetric keys. Symmetric (oct) keys have no
+// public form, they are either dropped or reported as an error, depending on octHandling.
+// The function returns the Keyset as a JSON string.
+func createPublicJWKKeyset(keys types.List, octHandling string) (string, error) {
+	Keyset := JWKKeyset{
+		Keys: make([]json.RawMessage, 0, len(keys.Elements())),
+	}
+
+	for i, key := range keys.Elements() {
+		keyStr, ok := key.(types.String)
+		if !ok {
+			return "", fmt.Errorf("unexpected type for key JSON: %T", key)
+		}
+
+		privateJWK, err := json2jwk(keyStr.ValueString())
+		if err != nil {
+			return "", fmt.Errorf("key #%d: %w", i, err)
+		}
+
+		if privateJWK.KeyType() == jwa.OctetSeq {
+			if octHandling == octKeysError {
+				return "", fmt.Errorf("key #%d (kid '%s') is a symmetric key, which has no public form", i, privateJWK.KeyID())
+			}
+			continue
+		}
+
+		publicJWK, err := privateJWK.PublicKey()
+		if err != nil {
+			return "", fmt.Errorf("key #%d: failed to extract public key: %w", i, err)
+		}
+
+		raw, err := json.Marshal(publicJWK)
+		if err != nil {
+			return "", fmt.Errorf("key #%d: failed to marshal public key: %w", i, err)
+		}
+		Keyset.Keys = append(Keyset.Keys, raw)
+	}
+
+	result, err := json.Marshal(Keyset)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal keyset: %v", err)
+	}
+
+	return string(result), nil
+}
+
+// Check that given keys have unique Key IDs. Key IDs generated by the key resources are
+// unknown during validation, so the check is repeated when the keyset is created.
+func checkDuplicateKIDs(keys types.List) error {
+	seenKids := make(map[string]bool)
+
+	for i, key := range keys.Elements() {
+		keyStr, ok := key.(types.String)
+		if !ok {
+			return fmt.Errorf("unexpected type for key JSON: %T", key)
+		}
+
+		parsed, err := json2jwk(keyStr.ValueString())
+		if err != nil {
+			return fmt.Errorf("key #%d: %w", i, err)
+		}
+
+		if seenKids[parsed.KeyID()] {
+			return fmt.Errorf("duplicate key id (kid) '%s'", parsed.KeyID())
+		}
+		seenKids[parsed.KeyID()] = true
+	}
+
+	return nil
+}
+
 func json2jwk(jwkJSON string) (jwk.Key, error) {
 	key, err := jwk.ParseKey([]byte(jwkJSON))
 	if err != nil {
@@ -154,10 +247,81 @@ func generateECJWK(kid, use, alg, crv string) (jwk.Key, error) {
 	return key, nil
 }
 
+// Create OKP (Octet Key Pair) JWK using given kid, use, alg and crv.
+// Edwards curves (Ed25519, Ed448) are used for signing (EdDSA),
+// Montgomery curves (X25519, X448) for ECDH-ES key agreement.
+// The function returns the private key as jwk.Key.
+func generateOKPJWK(kid, use, alg, crv string) (jwk.Key, error) {
+	var key jwk.Key
+	var err error
+
+	switch crv {
+	case "Ed25519":
+		_, edKey, genErr := ed25519.GenerateKey(rand.Reader)
+		if genErr != nil {
+			return nil, genErr
+		}
+		key, err = jwk.FromRaw(edKey)
+	case "X25519":
+		_, xKey, genErr := x25519.GenerateKey(rand.Reader)
+		if genErr != nil {
+			return nil, genErr
+		}
+		key, err = jwk.FromRaw(xKey)
+	case "Ed448":
+		pubKey, edKey, genErr := ed448.GenerateKey(rand.Reader)
+		if genErr != nil {
+			return nil, genErr
+		}
+		key, err = okpJWKFromBytes(crv, pubKey, edKey.Seed())
+	case "X448":
+		var secret, pubKey x448.Key
+		if _, genErr := rand.Read(secret[:]); genErr != nil {
+			return nil, genErr
+		}
+		x448.KeyGen(&pubKey, &secret)
+		key, err = okpJWKFromBytes(crv, pubKey[:], secret[:])
+	default:
+		return nil, fmt.Errorf("unsupported OKP curve: %s", crv)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if kid != "" {
+		_ = key.Set(jwk.KeyIDKey, kid)
+	}
+	if use != "" {
+		_ = key.Set(jwk.KeyUsageKey, use)
+	}
+	if alg != "" {
+		_ = key.Set(jwk.AlgorithmKey, alg)
+	}
+
+	return key, nil
+}
+
+// jwx can represent, but not create Ed448 and X448 keys from raw keys,
+// so the key is built from its JSON form (RFC 8037) instead.
+func okpJWKFromBytes(crv string, x, d []byte) (jwk.Key, error) {
+	raw, err := json.Marshal(map[string]string{
+		"kty": "OKP",
+		"crv": crv,
+		"x":   base64.RawURLEncoding.EncodeToString(x),
+		"d":   base64.RawURLEncoding.EncodeToString(d),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json2jwk(string(raw))
+}
+
 // Create oct key with given parameters
 func generateOctJWK(kid, use, alg string, numBytes int) (jwk.Key, error) {
-	if alg == "none" || alg == "dir" {
-		// Special case: "none" and "dir" algorithms don't need key material
+	if alg == "none" {
+		// Special case: "none" algorithm doesn't need key material. With "dir",
+		// the key is used as content encryption key and needs the given size.
 		numBytes = 1 // Use 1 byte to satisfy JWK structure
 	}
 
@@ -186,16 +350,596 @@ func generateOctJWK(kid, use, alg string, numBytes int) (jwk.Key, error) {
 	return key, nil
 }
 
+// Supported elliptic curves by name (crv). Optional curves, such as secp256k1,
+// are registered by build tag specific files.
+var ellipticCurves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+// Create JWK of given key type (kty). Curve and size are optional, when not given,
+// the curve required by the algorithm or a default curve and size is used.
+// The size is given in bits.
+func generateJWKOfType(kty, kid, use, alg, crv string, size int) (jwk.Key, error) {
+	if crv == "" {
+		crv = defaultKeyCurve(kty, use, alg)
+	}
+	if size == 0 {
+		size = defaultKeySize(kty)
+	}
+
+	switch kty {
+	case "RSA":
+		return generateRSAJWK(kid, use, alg, size)
+	case "EC":
+		return generateECJWK(kid, use, alg, crv)
+	case "OKP":
+		return generateOKPJWK(kid, use, alg, crv)
+	case "oct":
+		return generateOctJWK(kid, use, alg, size/8)
+	default:
+		return nil, fmt.Errorf("unsupported key type '%s'", kty)
+	}
+}
+
+// Curve of a generated key, when not given: the curve required by the algorithm, or the
+// default curve of the key type and use. Empty for key types without curve.
+func defaultKeyCurve(kty, use, alg string) string {
+	switch kty {
+	case "EC":
+		if crv, ok := ECSigningAlgorithmsToCurves[alg]; ok {
+			return crv
+		}
+		return "P-256"
+	case "OKP":
+		if use == "enc" {
+			return "X25519"
+		}
+		if crv, ok := OKPSigningAlgorithmsToCurves[alg]; ok {
+			return crv
+		}
+		return "Ed25519"
+	}
+	return ""
+}
+
+// Size of a generated key in bits, when not given. Zero for key types without size.
+func defaultKeySize(kty string) int {
+	switch kty {
+	case "RSA":
+		return 2048
+	case "oct":
+		return 256
+	}
+	return 0
+}
+
 // return the elliptic curve based on the given curve name
 func getEllipticCurve(curveName string) (elliptic.Curve, error) {
-	switch curveName {
-	case "P-256":
-		return elliptic.P256(), nil
-	case "P-384":
-		return elliptic.P384(), nil
-	case "P-521":
-		return elliptic.P521(), nil
-	default:
+	curve, ok := ellipticCurves[curveName]
+	if !ok {
 		return nil, fmt.Errorf("unsupported elliptic curve: %s", curveName)
 	}
+	return curve, nil
+}
+
+// --------------------------------------------------------------
+
+// Maximum size of a document fetched over HTTP(S)
+const maxHTTPResponseSize = 1 << 20
+
+// Options for fetching documents, such as JWKS, over HTTP(S)
+type httpOptions struct {
+	Timeout  time.Duration
+	CABundle string // PEM encoded CA certificates, used instead of system roots when given
+	Headers  map[string]string
+}
+
+// Maximum number of redirects followed, as by default in net/http
+const maxHTTPRedirects = 10
+
+// Follow redirects, but send the additional headers to the origin of the original request only.
+// Other than Authorization, net/http forwards them to any host.
+func (opts httpOptions) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxHTTPRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxHTTPRedirects)
+	}
+
+	if !sameOrigin(via[0].URL.String(), req.URL.String()) {
+		for name := range opts.Headers {
+			req.Header.Del(name)
+		}
+		req.Header.Set("Accept", "application/json")
+	}
+	return nil
+}
+
+// Fetch a document from the given URL using HTTP GET.
+// Any other than 2xx response is considered as an error.
+func fetchURL(ctx context.Context, url string, opts httpOptions) ([]byte, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.CABundle != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(opts.CABundle)) {
+			return nil, fmt.Errorf("no valid PEM certificates found in CA bundle")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	client := &http.Client{Timeout: opts.Timeout, Transport: transport, CheckRedirect: opts.checkRedirect}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range opts.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
+	}
+	if len(body) > maxHTTPResponseSize {
+		return nil, fmt.Errorf("response from %s exceeds %d bytes", url, maxHTTPResponseSize)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	return body, nil
+}
+
+// Whether the URLs have the same origin, i.e. the same scheme, host and port (RFC 6454).
+// Default ports are taken into account, e.g. 'https://example.com' and 'https://example.com:443'.
+func sameOrigin(a, b string) bool {
+	urlA, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	urlB, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	port := func(u *url.URL) string {
+		if p := u.Port(); p != "" {
+			return p
+		}
+		switch strings.ToLower(u.Scheme) {
+		case "http":
+			return "80"
+		case "https":
+			return "443"
+		}
+		return ""
+	}
+
+	return urlA.Host != "" &&
+		strings.EqualFold(urlA.Scheme, urlB.Scheme) &&
+		strings.EqualFold(urlA.Hostname(), urlB.Hostname()) &&
+		port(urlA) == port(urlB)
+}
+
+// Parse a JWK Set document. Every key is parsed and validated with jwx.
+// The function returns the parsed keys and their original JSON representations.
+func parseJWKSet(data []byte) ([]jwk.Key, []json.RawMessage, error) {
+	var keyset JWKKeyset
+	if err := json.Unmarshal(data, &keyset); err != nil {
+		return nil, nil, fmt.Errorf("invalid JWK Set: %w", err)
+	}
+	if keyset.Keys == nil {
+		return nil, nil, fmt.Errorf("invalid JWK Set: missing 'keys' member")
+	}
+
+	keys := make([]jwk.Key, 0, len(keyset.Keys))
+	for i, raw := range keyset.Keys {
+		key, err := json2jwk(string(raw))
+		if err != nil {
+			return nil, nil, fmt.Errorf("key #%d: %w", i, err)
+		}
+		if err := key.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("key #%d (kid '%s'): %w", i, key.KeyID(), err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, keyset.Keys, nil
+}
+
+// Get curve (crv) of the key. Returns empty string for keys without a curve (RSA, oct).
+func keyCurve(key jwk.Key) string {
+	if crv, ok := key.Get("crv"); ok {
+		return fmt.Sprintf("%s", crv)
+	}
+	return ""
+}
+
+// Strategies to generate the Key ID, when 'kid' is not given
+var validKIDStrategies = []string{"thumbprint", "uuid", "timestamp"}
+
+const defaultKIDStrategy = "thumbprint"
+
+// Generate Key ID for the key using given strategy:
+//   - thumbprint: RFC 7638 JWK Thumbprint, base64url encoded SHA-256
+//   - uuid: random UUID
+//   - timestamp: current time in RFC 3339 format, with nanoseconds
+func generateKID(key jwk.Key, strategy string) (string, error) {
+	switch strategy {
+	case "", "thumbprint":
+		return jwkThumbprint(key, defaultThumbprintHash)
+	case "uuid":
+		return uuid.GenerateUUID()
+	case "timestamp":
+		return time.Now().UTC().Format(time.RFC3339Nano), nil
+	default:
+		return "", fmt.Errorf("unsupported kid strategy '%s', expected one of %v", strategy, validKIDStrategies)
+	}
+}
+
+// Set generated Key ID to the key, unless the Key ID is given in configuration
+func setComputedKID(key jwk.Key, kid, strategy types.String) error {
+	if !kid.IsNull() && !kid.IsUnknown() {
+		return nil
+	}
+
+	generated, err := generateKID(key, strategy.ValueString())
+	if err != nil {
+		return err
+	}
+
+	return key.Set(jwk.KeyIDKey, generated)
+}
+
+// Update the metadata of an existing key to the planned values, keeping the key material.
+// A previous certificate is removed. The Key ID is left out, when it is not known yet.
+func updateKeyMetadata(key jwk.Key, kid, use, alg types.String) error {
+	for _, name := range []string{jwk.KeyIDKey, jwk.KeyUsageKey, jwk.AlgorithmKey,
+		jwk.X509CertChainKey, jwk.X509CertThumbprintKey, jwk.X509CertThumbprintS256Key} {
+		if err := key.Remove(name); err != nil {
+			return err
+		}
+	}
+
+	for name, value := range map[string]types.String{jwk.KeyIDKey: kid, jwk.KeyUsageKey: use, jwk.AlgorithmKey: alg} {
+		if value.ValueString() != "" {
+			if err := key.Set(name, value.ValueString()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Derive the Key ID of a JWK without 'kid' from its thumbprint.
+// Returns the Key ID and the JWK JSON including the Key ID.
+func thumbprintKID(jwkJSON string) (string, string, error) {
+	key, err := json2jwk(jwkJSON)
+	if err != nil {
+		return "", "", err
+	}
+
+	kid, err := generateKID(key, defaultKIDStrategy)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := key.Set(jwk.KeyIDKey, kid); err != nil {
+		return "", "", err
+	}
+
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		return "", "", err
+	}
+
+	return kid, string(keyJSON), nil
+}
+
+// Validate, that 'kid' and 'kid_strategy' are not both given. Values of 'kid_strategy' are checked by its validator.
+func validateKIDConfig(kid, strategy types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if strategy.IsNull() || strategy.IsUnknown() {
+		return diags
+	}
+
+	if !kid.IsNull() && !kid.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root("kid_strategy"),
+			"Conflicting 'kid' and 'kid_strategy' attributes",
+			"'kid_strategy' is only used when 'kid' is not given",
+		)
+	}
+
+	return diags
+}
+
+// Hash functions of JWK Thumbprints, named as in RFC 9278
+var thumbprintHashes = map[string]crypto.Hash{
+	"sha-1":   crypto.SHA1,
+	"sha-256": crypto.SHA256,
+	"sha-512": crypto.SHA512,
+}
+
+const defaultThumbprintHash = "SHA-256"
+
+// Prefix of JWK Thumbprint URIs, see RFC 9278
+const thumbprintURIPrefix = "urn:ietf:params:oauth:jwk-thumbprint:"
+
+// Hash functions of JWK Thumbprint URIs, which must be named in the IANA
+// "Named Information Hash Algorithm" registry. SHA-1 is not registered.
+var thumbprintURIHashes = []string{"sha-256", "sha-512"}
+
+// Compute RFC 7638 JWK Thumbprint of the key, base64url encoded.
+// The hash is given as 'SHA-1', 'SHA-256' or 'SHA-512'. With 'urn:' prefix,
+// e.g. 'urn:SHA-256', the thumbprint is returned as an RFC 9278 URI, which
+// supports SHA-256 and SHA-512 only.
+func jwkThumbprint(key jwk.Key, hash string) (string, error) {
+	name := strings.ToLower(hash)
+	name, uri := strings.CutPrefix(name, "urn:")
+
+	h, ok := thumbprintHashes[name]
+	if !ok {
+		return "", fmt.Errorf("unsupported hash '%s', expected one of SHA-1, SHA-256, SHA-512, optionally prefixed with 'urn:'", hash)
+	}
+	if uri && !isValid(name, thumbprintURIHashes) {
+		return "", fmt.Errorf("unsupported hash '%s' for JWK Thumbprint URI, expected urn:SHA-256 or urn:SHA-512", hash)
+	}
+
+	thumbprint, err := key.Thumbprint(h)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute JWK thumbprint: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(thumbprint)
+	if uri {
+		return thumbprintURIPrefix + name + ":" + encoded, nil
+	}
+	return encoded, nil
+}
+
+// Compute SHA-256 JWK Thumbprint of the key given in JSON format
+func jsonThumbprint(jwkJSON string) (string, error) {
+	key, err := json2jwk(jwkJSON)
+	if err != nil {
+		return "", err
+	}
+	return jwkThumbprint(key, defaultThumbprintHash)
+}
+
+// JSON, public JSON and SHA-256 JWK Thumbprint of the key, as returned by ephemeral
+// resources. Public JSON is empty for symmetric keys.
+func ephemeralKeyValues(key jwk.Key) (string, string, string, error) {
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to compute key thumbprint: %w", err)
+	}
+
+	if key.KeyType() == jwa.OctetSeq {
+		return string(keyJSON), "", thumbprint, nil
+	}
+
+	publicKey, err := key.PublicKey()
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to extract public key: %w", err)
+	}
+	publicJSON, err := json.Marshal(publicKey)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return string(keyJSON), string(publicJSON), thumbprint, nil
+}
+
+// PEM encodings of keys
+const (
+	pemFormatPKCS1 = "pkcs1" // RSA private key, 'RSA PRIVATE KEY'
+	pemFormatSEC1  = "sec1"  // EC private key, 'EC PRIVATE KEY'
+	pemFormatPKCS8 = "pkcs8" // Any private key, 'PRIVATE KEY'
+	pemFormatSPKI  = "spki"  // Any public key, 'PUBLIC KEY'
+)
+
+// Curves of EC keys, which can be encoded in PEM by the standard library
+var pemECCurves = []string{"P-256", "P-384", "P-521"}
+
+// Check, whether the key contains private key material
+func isPrivateJWK(key jwk.Key) bool {
+	switch key.(type) {
+	case jwk.RSAPrivateKey, jwk.ECDSAPrivateKey, jwk.OKPPrivateKey, jwk.SymmetricKey:
+		return true
+	}
+	return false
+}
+
+// Encode the key in PEM format. Private keys can be encoded in 'pkcs1' (RSA),
+// 'sec1' (EC) and 'pkcs8' formats, public keys in 'spki' format. If a private
+// key is encoded in 'spki' format, its public key is encoded.
+func jwkToPEM(key jwk.Key, format string) (string, error) {
+	if format == pemFormatSPKI {
+		publicKey, err := key.PublicKey()
+		if err != nil {
+			return "", fmt.Errorf("failed to extract public key: %w", err)
+		}
+		key = publicKey
+	} else if !isPrivateJWK(key) {
+		return "", fmt.Errorf("format '%s' requires a private key", format)
+	}
+
+	if key.KeyType() == jwa.OctetSeq {
+		return "", fmt.Errorf("symmetric keys cannot be encoded in PEM")
+	}
+
+	var raw interface{}
+	if err := key.Raw(&raw); err != nil {
+		return "", fmt.Errorf("failed to get raw key: %w", err)
+	}
+
+	// X25519 keys are encoded by crypto/ecdh
+	switch k := raw.(type) {
+	case x25519.PrivateKey:
+		ecdhKey, err := ecdh.X25519().NewPrivateKey(k.Seed())
+		if err != nil {
+			return "", err
+		}
+		raw = ecdhKey
+	case x25519.PublicKey:
+		ecdhKey, err := ecdh.X25519().NewPublicKey(k)
+		if err != nil {
+			return "", err
+		}
+		raw = ecdhKey
+	}
+
+	var der []byte
+	var blockType string
+	var err error
+
+	switch format {
+	case pemFormatPKCS1:
+		rsaKey, ok := raw.(*rsa.PrivateKey)
+		if !ok {
+			return "", fmt.Errorf("format '%s' requires an RSA key", format)
+		}
+		der, blockType = x509.MarshalPKCS1PrivateKey(rsaKey), "RSA PRIVATE KEY"
+	case pemFormatSEC1:
+		ecKey, ok := raw.(*ecdsa.PrivateKey)
+		if !ok {
+			return "", fmt.Errorf("format '%s' requires an EC key", format)
+		}
+		der, err = x509.MarshalECPrivateKey(ecKey)
+		blockType = "EC PRIVATE KEY"
+	case pemFormatPKCS8:
+		der, err = x509.MarshalPKCS8PrivateKey(raw)
+		blockType = "PRIVATE KEY"
+	case pemFormatSPKI:
+		der, err = x509.MarshalPKIXPublicKey(raw)
+		blockType = "PUBLIC KEY"
+	default:
+		return "", fmt.Errorf("unsupported PEM format '%s', expected one of %s, %s, %s, %s",
+			format, pemFormatPKCS1, pemFormatSEC1, pemFormatPKCS8, pemFormatSPKI)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode key in %s format: %w", format, err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})), nil
+}
+
+// Encode the key in PEM formats used by the key resources: the private key in
+// given traditional format and in PKCS#8 format, and the public key in SPKI format.
+// Private key encodings are empty for public keys, and all encodings are empty
+// for curves, which are not supported by the standard library.
+func keyPEMs(key jwk.Key, privateFormat string) (string, string, string, error) {
+	if key.KeyType() == jwa.EC && !isValid(keyCurve(key), pemECCurves) {
+		return "", "", "", nil
+	}
+
+	publicPEM, err := jwkToPEM(key, pemFormatSPKI)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if !isPrivateJWK(key) {
+		return "", "", publicPEM, nil
+	}
+
+	privatePEM, err := jwkToPEM(key, privateFormat)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	pkcs8PEM, err := jwkToPEM(key, pemFormatPKCS8)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return privatePEM, pkcs8PEM, publicPEM, nil
+}
+
+// Parse a key from the first PEM block. Supported blocks are PKCS#1 ('RSA PRIVATE KEY',
+// 'RSA PUBLIC KEY'), SEC 1 ('EC PRIVATE KEY'), PKCS#8 ('PRIVATE KEY'), SPKI ('PUBLIC KEY')
+// and X.509 certificates ('CERTIFICATE'). The certificate is included in 'x5c' of the key.
+func pemToJWK(pemData string) (jwk.Key, error) {
+	block, _ := pem.Decode([]byte(pemData))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	var raw interface{}
+	var err error
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		raw, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		raw, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		raw, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		raw, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		raw, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var certificate *x509.Certificate
+		certificate, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			raw = certificate.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type '%s'", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", block.Type, err)
+	}
+
+	// X25519 keys are parsed as crypto/ecdh keys
+	switch k := raw.(type) {
+	case *ecdh.PrivateKey:
+		if k.Curve() != ecdh.X25519() {
+			return nil, fmt.Errorf("unsupported ECDH curve %s", k.Curve())
+		}
+		raw, err = x25519.NewKeyFromSeed(k.Bytes())
+		if err != nil {
+			return nil, err
+		}
+	case *ecdh.PublicKey:
+		if k.Curve() != ecdh.X25519() {
+			return nil, fmt.Errorf("unsupported ECDH curve %s", k.Curve())
+		}
+		raw = x25519.PublicKey(k.Bytes())
+	}
+
+	key, err := jwk.FromRaw(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWK: %w", err)
+	}
+
+	if block.Type == "CERTIFICATE" {
+		var chain cert.Chain
+		if err := chain.Add([]byte(base64.StdEncoding.EncodeToString(block.Bytes))); err != nil {
+			return nil, err
+		}
+		if err := key.Set(jwk.X509CertChainKey, &chain); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
 }
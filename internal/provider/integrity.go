@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+
+	"github.com/cloudflare/circl/dh/x448"
+	"github.com/cloudflare/circl/sign/ed448"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/x25519"
+)
+
+// Parse a key read from state, and check that it has the key type of the resource
+// and that its components are consistent.
+func parseStateKey(keyJSON string, kty jwa.KeyType) (jwk.Key, error) {
+	key, err := json2jwk(keyJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.KeyType() != kty {
+		return nil, fmt.Errorf("expected key type '%s', got '%s'", kty, key.KeyType())
+	}
+
+	if err := checkKeyIntegrity(key); err != nil {
+		return nil, fmt.Errorf("integrity check failed for key '%s': %w", key.KeyID(), err)
+	}
+
+	return key, nil
+}
+
+// Check, that the components of the key are consistent: the RSA modulus is the product
+// of the primes, the EC point is on the curve, and the public key of EC and OKP keys
+// matches the private key.
+func checkKeyIntegrity(key jwk.Key) error {
+	switch key.KeyType() {
+	case jwa.RSA:
+		return checkRSAKeyIntegrity(key)
+	case jwa.EC:
+		return checkECKeyIntegrity(key)
+	case jwa.OKP:
+		return checkOKPKeyIntegrity(key)
+	case jwa.OctetSeq:
+		var raw []byte
+		if err := key.Raw(&raw); err != nil {
+			return err
+		}
+		if len(raw) == 0 {
+			return fmt.Errorf("symmetric key is empty")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type '%s'", key.KeyType())
+	}
+}
+
+func checkRSAKeyIntegrity(key jwk.Key) error {
+	if !isPrivateJWK(key) {
+		var public rsa.PublicKey
+		if err := key.Raw(&public); err != nil {
+			return err
+		}
+		if public.N.Sign() <= 0 || public.E < 3 {
+			return fmt.Errorf("invalid RSA public key")
+		}
+		return nil
+	}
+
+	var private rsa.PrivateKey
+	if err := key.Raw(&private); err != nil {
+		return err
+	}
+	// Checks n = p*q and d*e = 1 mod lcm(p-1, q-1)
+	if err := private.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func checkECKeyIntegrity(key jwk.Key) error {
+	var public *ecdsa.PublicKey
+
+	if isPrivateJWK(key) {
+		var private ecdsa.PrivateKey
+		if err := key.Raw(&private); err != nil {
+			return err
+		}
+		public = &private.PublicKey
+
+		x, y := public.Curve.ScalarBaseMult(private.D.Bytes())
+		if x.Cmp(public.X) != 0 || y.Cmp(public.Y) != 0 {
+			return fmt.Errorf("EC public key does not match the private key")
+		}
+	} else {
+		public = &ecdsa.PublicKey{}
+		if err := key.Raw(public); err != nil {
+			return err
+		}
+	}
+
+	if !public.Curve.IsOnCurve(public.X, public.Y) {
+		return fmt.Errorf("EC point is not on curve '%s'", keyCurve(key))
+	}
+	return nil
+}
+
+func checkOKPKeyIntegrity(key jwk.Key) error {
+	okp, ok := key.(jwk.OKPPrivateKey)
+	if !ok {
+		return nil // Public key has no components to compare
+	}
+
+	var public []byte
+	switch crv := keyCurve(key); crv {
+	case "Ed25519":
+		if len(okp.D()) != ed25519.SeedSize {
+			return fmt.Errorf("invalid Ed25519 private key size")
+		}
+		public = ed25519.NewKeyFromSeed(okp.D()).Public().(ed25519.PublicKey)
+	case "X25519":
+		private, err := x25519.NewKeyFromSeed(okp.D())
+		if err != nil {
+			return err
+		}
+		public = private.Public().(x25519.PublicKey)
+	case "Ed448":
+		if len(okp.D()) != ed448.SeedSize {
+			return fmt.Errorf("invalid Ed448 private key size")
+		}
+		public = ed448.NewKeyFromSeed(okp.D()).Public().(ed448.PublicKey)
+	case "X448":
+		var secret, publicKey x448.Key
+		if len(okp.D()) != len(secret) {
+			return fmt.Errorf("invalid X448 private key size")
+		}
+		copy(secret[:], okp.D())
+		x448.KeyGen(&publicKey, &secret)
+		public = publicKey[:]
+	default:
+		return fmt.Errorf("unsupported OKP curve '%s'", crv)
+	}
+
+	if !bytes.Equal(public, okp.X()) {
+		return fmt.Errorf("OKP public key does not match the private key")
+	}
+	return nil
+}
+
+// Value of an attribute read from the key in state. The current value is kept, when it
+// matches, so that null and empty values do not show up as changes. Otherwise the value
+// of the key is used, and the difference to the configuration shows up in plan.
+func keyAttributeValue(current types.String, value string) types.String {
+	if current.ValueString() == value {
+		return current
+	}
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// Size of the modulus of an RSA key in bits
+func rsaKeyBits(key jwk.Key) int {
+	switch k := key.(type) {
+	case jwk.RSAPrivateKey:
+		return new(big.Int).SetBytes(k.N()).BitLen()
+	case jwk.RSAPublicKey:
+		return new(big.Int).SetBytes(k.N()).BitLen()
+	}
+	return 0
+}
@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwe"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Serializations of JWE
+const (
+	jweSerializationCompact = "compact"
+	jweSerializationJSON    = "json"
+)
+
+// Content encryption, when 'enc' is not given
+const defaultContentEncryption = "A256GCM"
+
+// Sizes of content encryption keys in bytes. With 'dir', the key must have this size.
+var contentEncryptionKeySizes = map[string]int{
+	"A128GCM":       16,
+	"A192GCM":       24,
+	"A256GCM":       32,
+	"A128CBC-HS256": 32,
+	"A192CBC-HS384": 48,
+	"A256CBC-HS512": 64,
+}
+
+// Key management algorithm of the key for JWE
+func keyEncryptionAlgorithm(key jwk.Key) (jwa.KeyEncryptionAlgorithm, error) {
+	var alg jwa.KeyEncryptionAlgorithm
+
+	if key.KeyUsage() == "sig" {
+		return alg, fmt.Errorf("key '%s' is a signing key (use 'sig') and cannot be used for encryption", key.KeyID())
+	}
+	if key.Algorithm().String() == "" {
+		return alg, fmt.Errorf("key '%s' has no 'alg'", key.KeyID())
+	}
+	if err := alg.Accept(key.Algorithm().String()); err != nil {
+		return alg, fmt.Errorf("unsupported key management algorithm '%s'", key.Algorithm())
+	}
+	if crv := keyCurve(key); crv == "X448" {
+		return alg, fmt.Errorf("curve '%s' is not supported for JWE", crv)
+	}
+
+	return alg, nil
+}
+
+// Encrypt the plaintext to the key, using the key management algorithm of the key
+// and the given content encryption. Private asymmetric keys are encrypted to their
+// public key.
+func encryptJWE(key jwk.Key, plaintext string, enc string, serialization string) (string, error) {
+	alg, err := keyEncryptionAlgorithm(key)
+	if err != nil {
+		return "", err
+	}
+
+	if enc == "" {
+		enc = defaultContentEncryption
+	}
+	keySize, ok := contentEncryptionKeySizes[enc]
+	if !ok {
+		return "", fmt.Errorf("unsupported content encryption '%s'", enc)
+	}
+
+	if alg == jwa.DIRECT {
+		var cek []byte
+		if err := key.Raw(&cek); err != nil {
+			return "", fmt.Errorf("failed to get content encryption key: %w", err)
+		}
+		if len(cek) != keySize {
+			return "", fmt.Errorf("content encryption '%s' requires a %d bit key with 'dir', got %d bits", enc, keySize*8, len(cek)*8)
+		}
+	}
+
+	options := []jwe.EncryptOption{jwe.WithContentEncryption(jwa.ContentEncryptionAlgorithm(enc))}
+	switch strings.ToLower(serialization) {
+	case "", jweSerializationCompact:
+		options = append(options, jwe.WithCompact())
+	case jweSerializationJSON:
+		options = append(options, jwe.WithJSON())
+	default:
+		return "", fmt.Errorf("unsupported serialization '%s', expected '%s' or '%s'", serialization, jweSerializationCompact, jweSerializationJSON)
+	}
+
+	if key.KeyType() != jwa.OctetSeq && isPrivateJWK(key) {
+		if key, err = key.PublicKey(); err != nil {
+			return "", fmt.Errorf("failed to extract public key: %w", err)
+		}
+	}
+	options = append(options, jwe.WithKey(alg, key))
+
+	encrypted, err := jwe.Encrypt([]byte(plaintext), options...)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt: %w", err)
+	}
+
+	return string(encrypted), nil
+}
+
+// Decrypt a JWE in compact or JSON serialization with the private key
+func decryptJWE(key jwk.Key, encrypted string) (string, error) {
+	if !isPrivateJWK(key) {
+		return "", fmt.Errorf("a private key is required for decryption")
+	}
+
+	alg, err := keyEncryptionAlgorithm(key)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := jwe.Decrypt([]byte(strings.TrimSpace(encrypted)), jwe.WithKey(alg, key))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return string(plaintext), nil
+}
@@ -0,0 +1,82 @@
+//go:build jwx_es256k
+// +build jwx_es256k
+
+package provider_test
+
+import (
+	"os"
+	"testing"
+
+	"terraform-provider-jwk/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestECKey_ES256K(t *testing.T) {
+	os.Setenv("TF_ACC", "true")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_ec_key" "example" {
+  kid = "test-key"
+  use = "sig"
+  alg = "ES256K"
+  crv = "secp256k1"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_ec_key.example", "alg", "ES256K"),
+					resource.TestCheckResourceAttr("jwk_ec_key.example", "crv", "secp256k1"),
+					resource.TestCheckResourceAttrSet("jwk_ec_key.example", "json"),
+				),
+			},
+		},
+	})
+}
+
+func TestJwkECKeyResource_ImportES256K(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	testKey := `{
+        "kid": "imported-es256k-key",
+        "kty": "EC",
+        "use": "sig",
+        "alg": "ES256K",
+        "crv": "secp256k1",
+        "x": "SJarCqV1g-y_ZYHV0L5ERXhE775xqDMO-pzzcuAoK3Q",
+        "y": "xqrHESB5gW2YtEVgnUyrRhyvZcFIsExwdHJKBuL1X1w"
+    }`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `provider "jwk" {}
+				resource "jwk_ec_key" "test" {
+				# (resource arguments)
+				}`,
+				ImportState:                          true,
+				ImportStateId:                        testKey,
+				ImportStateVerify:                    false,
+				ImportStateVerifyIdentifierAttribute: "kid",
+				ResourceName:                         "jwk_ec_key.test",
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_ec_key.test", "kid", "imported-es256k-key"),
+					resource.TestCheckResourceAttr("jwk_ec_key.test", "crv", "secp256k1"),
+					resource.TestCheckResourceAttr("jwk_ec_key.test", "alg", "ES256K"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,67 @@
+package provider_test
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"terraform-provider-jwk/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestKeyInfo_Basic(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_ec_key" "example" {
+  kid = "ec-1"
+  use = "sig"
+  alg = "ES384"
+  crv = "P-384"
+}
+
+data "jwk_key_info" "example" {
+  json = jwk_ec_key.example.json
+}
+
+data "jwk_key_info" "oct" {
+  json = jsonencode({ kty = "oct", k = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", key_ops = ["sign", "verify"] })
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jwk_key_info.example", "kty", "EC"),
+					resource.TestCheckResourceAttr("data.jwk_key_info.example", "kid", "ec-1"),
+					resource.TestCheckResourceAttr("data.jwk_key_info.example", "crv", "P-384"),
+					resource.TestCheckResourceAttr("data.jwk_key_info.example", "size", "384"),
+					resource.TestCheckResourceAttr("data.jwk_key_info.example", "is_private", "true"),
+					resource.TestCheckResourceAttr("data.jwk_key_info.example", "key_ops.#", "0"),
+					resource.TestCheckResourceAttrPair("data.jwk_key_info.example", "thumbprint", "jwk_ec_key.example", "thumbprint"),
+					resource.TestCheckResourceAttrSet("data.jwk_key_info.example", "public_json"),
+
+					resource.TestCheckResourceAttr("data.jwk_key_info.oct", "size", "256"),
+					resource.TestCheckResourceAttr("data.jwk_key_info.oct", "key_ops.#", "2"),
+					resource.TestCheckResourceAttr("data.jwk_key_info.oct", "use", ""),
+					resource.TestCheckResourceAttr("data.jwk_key_info.oct", "public_json", ""),
+				),
+			},
+			{
+				Config: `
+data "jwk_key_info" "example" {
+  json = jsonencode({ kty = "EC", crv = "P-256" })
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid JWK`),
+			},
+		},
+	})
+}
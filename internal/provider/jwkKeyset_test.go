@@ -1,8 +1,13 @@
 package provider_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -10,6 +15,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -66,7 +72,249 @@ resource "jwk_keyset" "example" {
 	})
 }
 
+func Test_Keyset_publicJSON(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	config := `
+resource "jwk_ec_key" "ec1" {
+  kid = "ec1"
+  use = "sig"
+  alg = "ES256"
+  crv = "P-256"
+}
+
+resource "jwk_oct_key" "oct1" {
+  kid = "oct1"
+  use = "sig"
+  size = 256
+}
+
+resource "jwk_keyset" "example" {
+  keys = [
+    jwk_ec_key.ec1.json,
+    jwk_oct_key.oct1.json,
+  ]
+  %s
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, ""),
+				Check: resource.ComposeTestCheckFunc(
+					// Check, that public keyset contains the public EC key only
+					resource.TestCheckResourceAttrWith("jwk_keyset.example", "public_json", func(value string) error {
+						if !containsSubstring(value, `"kid":"ec1"`) {
+							return fmt.Errorf("public keyset JSON doesn't contain ec1 key")
+						}
+						if containsSubstring(value, `"kid":"oct1"`) {
+							return fmt.Errorf("public keyset JSON contains oct1 key")
+						}
+						if containsSubstring(value, `"d":`) {
+							return fmt.Errorf("public keyset JSON contains private key material")
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(config, `public_oct_keys = "error"`),
+				ExpectError: regexp.MustCompile(`no public form`),
+			},
+			{
+				Config:      fmt.Sprintf(config, `public_oct_keys = "keep"`),
+				ExpectError: regexp.MustCompile(`Invalid attribute value for 'public_oct_keys'`),
+			},
+		},
+	})
+}
+
+func Test_Keyset_duplicateComputedKID(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				// Key ID of the key is unknown, when the keyset is validated
+				Config: `
+resource "jwk_ec_key" "ec1" {
+  use = "sig"
+  crv = "P-256"
+}
+
+resource "jwk_keyset" "example" {
+  keys = [
+    jwk_ec_key.ec1.json,
+    provider::jwk::public_key(jwk_ec_key.ec1.json, ""),
+  ]
+}
+`,
+				ExpectError: regexp.MustCompile(`Duplicate key id`),
+			},
+		},
+	})
+}
+
+func Test_Keyset_selection(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_ec_key" "sign" {
+  kid = "sign"
+  use = "sig"
+  alg = "ES256"
+}
+
+resource "jwk_rsa_key" "encrypt" {
+  kid  = "encrypt"
+  use  = "enc"
+  alg  = "RSA-OAEP-256"
+  size = 2048
+}
+
+resource "jwk_keyset" "pool" {
+  keys = [
+    jwk_rsa_key.encrypt.json,
+    jwk_ec_key.sign.json,
+  ]
+}
+
+resource "jwk_keyset" "signing" {
+  source_keysets = [jwk_keyset.pool.json]
+
+  filter {
+    use = "sig"
+  }
+}
+
+resource "jwk_keyset" "all" {
+  keys = [
+    jwk_ec_key.sign.json,
+    provider::jwk::public_key(jwk_ec_key.sign.json, ""),
+  ]
+  source_keysets = [jwk_keyset.pool.json]
+  deduplicate    = true
+  sort           = "kid"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("jwk_keyset.signing", "json", func(value string) error {
+						if !containsSubstring(value, `"kid":"sign"`) || containsSubstring(value, `"kid":"encrypt"`) {
+							return fmt.Errorf("keyset JSON doesn't contain only the signing key: %s", value)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("jwk_keyset.all", "json", func(value string) error {
+						if strings.Count(value, `"kid"`) != 2 {
+							return fmt.Errorf("keyset JSON isn't de-duplicated: %s", value)
+						}
+						if strings.Index(value, `"kid":"encrypt"`) > strings.Index(value, `"kid":"sign"`) {
+							return fmt.Errorf("keyset JSON isn't sorted by kid: %s", value)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: `
+resource "jwk_keyset" "example" {
+  keys = []
+
+  filter {
+    kty = ["AES"]
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid key type in 'kty'`),
+			},
+			{
+				Config: `
+resource "jwk_keyset" "example" {
+  source_keysets = [jsonencode({ kty = "oct" })]
+}
+`,
+				ExpectError: regexp.MustCompile(`missing 'keys' member`),
+			},
+		},
+	})
+}
+
 // helper function to check if string contains substring
+func Test_Keyset_read(t *testing.T) {
+	server := newStateTestServer(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyJSON := testKeyJSON(t, key, "key-1", "sig", "ES256")
+	otherJSON := testKeyJSON(t, other, "key-1", "sig", "ES256")
+
+	var otherMembers map[string]any
+	if err := json.Unmarshal([]byte(otherJSON), &otherMembers); err != nil {
+		t.Fatal(err)
+	}
+
+	config := func(keyJSON string) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"keys": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, keyJSON),
+			}),
+		}
+	}
+
+	// Key set in state, which doesn't match the keys any more, is recomputed
+	state := config(keyJSON)
+	state["json"] = tftypes.NewValue(tftypes.String, `{"keys":[]}`)
+	state["public_json"] = tftypes.NewValue(tftypes.String, `{"keys":[]}`)
+
+	read, diags := server.read("jwk_keyset", state)
+	if diags != nil {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	var keysetJSON string
+	if err := read["json"].As(&keysetJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !containsSubstring(keysetJSON, `"kid":"key-1"`) {
+		t.Fatalf("expected key set to be recomputed from the keys, got %s", keysetJSON)
+	}
+	if server.planHasChanges("jwk_keyset", config(keyJSON), read) {
+		t.Fatal("expected empty plan for recomputed key set")
+	}
+
+	// Tampered key is reported at its position in keys
+	state = config(withJWKMembers(t, keyJSON, map[string]any{"y": otherMembers["y"]}))
+	_, diags = server.read("jwk_keyset", state)
+	if !hasDiagnostic(diags, "integrity check failed") {
+		t.Fatalf("expected integrity check to fail, got %v", diags)
+	}
+	at := tftypes.NewAttributePath().WithAttributeName("keys").WithElementKeyInt(0)
+	if !diags[0].Attribute.Equal(at) {
+		t.Fatalf("expected diagnostic at %s, got %s", at, diags[0].Attribute)
+	}
+}
+
 func containsSubstring(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
@@ -0,0 +1,187 @@
+package provider_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"testing"
+
+	"terraform-provider-jwk/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// Starts an OpenID Provider serving metadata and keys. Metadata announces
+// given issuer, or the URL of the server itself, when issuer is empty.
+func newOIDCServer(issuer string) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	if issuer == "" {
+		issuer = server.URL
+	}
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+  "issuer": "%s",
+  "jwks_uri": "%s/keys",
+  "authorization_endpoint": "%s/authorize",
+  "token_endpoint": "%s/token",
+  "id_token_signing_alg_values_supported": ["ES256", "EdDSA"]
+}`, issuer, server.URL, server.URL, server.URL)
+	})
+
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"keys":[
+  {"kty":"OKP","crv":"Ed25519","kid":"idp-sig-1","use":"sig","alg":"EdDSA","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+  {"kty":"EC","crv":"P-256","kid":"idp-enc-1","use":"enc","alg":"ECDH-ES","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}
+]}`)
+	})
+
+	return server
+}
+
+func TestOIDCDiscovery_Basic(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	server := newOIDCServer("")
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "jwk_oidc_discovery" "idp" {
+  issuer = "%s"
+}
+`, server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jwk_oidc_discovery.idp", "jwks_uri", server.URL+"/keys"),
+					resource.TestCheckResourceAttr("data.jwk_oidc_discovery.idp", "token_endpoint", server.URL+"/token"),
+					resource.TestCheckResourceAttr("data.jwk_oidc_discovery.idp", "id_token_signing_alg_values_supported.#", "2"),
+					resource.TestCheckResourceAttr("data.jwk_oidc_discovery.idp", "signing_kids.#", "1"),
+					resource.TestCheckResourceAttr("data.jwk_oidc_discovery.idp", "signing_kids.0", "idp-sig-1"),
+					resource.TestCheckResourceAttr("data.jwk_oidc_discovery.idp", "signing_keys.0.crv", "Ed25519"),
+					resource.TestCheckResourceAttrWith("data.jwk_oidc_discovery.idp", "jwks_json", func(value string) error {
+						if !containsSubstring(value, `"kid":"idp-enc-1"`) {
+							return fmt.Errorf("keyset JSON doesn't contain idp-enc-1 key")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestOIDCDiscovery_IssuerMismatch(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	server := newOIDCServer("https://attacker.example.com")
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "jwk_oidc_discovery" "idp" {
+  issuer = "%s"
+}
+`, server.URL),
+				ExpectError: regexp.MustCompile(`Issuer mismatch`),
+			},
+		},
+	})
+}
+
+func TestOIDCDiscovery_Headers(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	// Key set of another origin fails, when it receives the credentials of the issuer
+	keysServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("X-Api-Key") != "" {
+			http.Error(w, "unexpected credentials", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"keys":[
+  {"kty":"OKP","crv":"Ed25519","kid":"idp-sig-1","use":"sig","alg":"EdDSA","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}
+]}`)
+	}))
+	defer keysServer.Close()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "missing credentials", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"issuer": "%s", "jwks_uri": "%s/keys"}`, server.URL, keysServer.URL)
+	})
+
+	// Key set of the issuer origin, which redirects to another origin
+	mux.HandleFunc("/redirect/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"issuer": "%s/redirect", "jwks_uri": "%s/redirect/keys"}`, server.URL, server.URL)
+	})
+	mux.HandleFunc("/redirect/keys", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "test-key" {
+			http.Error(w, "missing credentials", http.StatusUnauthorized)
+			return
+		}
+		http.Redirect(w, r, keysServer.URL+"/keys", http.StatusFound)
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "jwk_oidc_discovery" "idp" {
+  issuer  = "%s"
+  headers = {
+    Authorization = "Bearer test-token"
+  }
+}
+`, server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jwk_oidc_discovery.idp", "jwks_uri", keysServer.URL+"/keys"),
+					resource.TestCheckResourceAttr("data.jwk_oidc_discovery.idp", "signing_kids.0", "idp-sig-1"),
+				),
+			},
+			{
+				// Headers are not forwarded on a redirect to another origin
+				Config: fmt.Sprintf(`
+data "jwk_oidc_discovery" "idp" {
+  issuer  = "%s/redirect"
+  headers = {
+    X-Api-Key = "test-key"
+  }
+}
+`, server.URL),
+				Check: resource.TestCheckResourceAttr("data.jwk_oidc_discovery.idp", "signing_kids.0", "idp-sig-1"),
+			},
+		},
+	})
+}
@@ -0,0 +1,413 @@
+package provider_test
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"terraform-provider-jwk/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestOKPKey_Basic(t *testing.T) {
+	os.Setenv("TF_ACC", "true")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_okp_key" "example" {
+  kid = "test-key"
+  use = "sig"
+  alg = "EdDSA"
+  crv = "Ed25519"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_okp_key.example", "kid", "test-key"),
+					resource.TestCheckResourceAttr("jwk_okp_key.example", "alg", "EdDSA"),
+					resource.TestCheckResourceAttr("jwk_okp_key.example", "use", "sig"),
+					resource.TestCheckResourceAttrWith("jwk_okp_key.example", "json", func(value string) error {
+						if !containsSubstring(value, `"kty":"OKP"`) || !containsSubstring(value, `"crv":"Ed25519"`) {
+							return fmt.Errorf("expected Ed25519 OKP key, got %s", value)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestOKPKey_AlgForSignature(t *testing.T) {
+	// Iterate through signature algorithms
+	for alg := range provider.OKPSigAlgorithms {
+		crv, ok := provider.OKPSigningAlgorithmsToCurves[alg]
+		if !ok {
+			crv = "Ed25519" // EdDSA works with any Edwards curve
+		}
+
+		t.Run(alg, func(t *testing.T) {
+			os.Setenv("TF_ACC", "true")
+			defer os.Unsetenv("TF_ACC")
+
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+					"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+				},
+				Steps: []resource.TestStep{
+					{
+						Config: fmt.Sprintf(`
+resource "jwk_okp_key" "example" {
+  kid = "test-key"
+  use = "sig"
+  alg = "%s"
+  crv = "%s"
+}
+`, alg, crv),
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestCheckResourceAttr("jwk_okp_key.example", "kid", "test-key"),
+							resource.TestCheckResourceAttr("jwk_okp_key.example", "alg", alg),
+						),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestOKPKey_AlgForEncryption(t *testing.T) {
+	// Iterate through encryption algorithms
+	for alg := range provider.ECEncAlgorithms {
+
+		t.Run(alg, func(t *testing.T) {
+			os.Setenv("TF_ACC", "true")
+			defer os.Unsetenv("TF_ACC")
+
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+					"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+				},
+				Steps: []resource.TestStep{
+					{
+						Config: fmt.Sprintf(`
+resource "jwk_okp_key" "example" {
+  kid = "test-key"
+  use = "enc"
+  alg = "%s"
+  crv = "X25519"
+}
+`, alg),
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestCheckResourceAttr("jwk_okp_key.example", "kid", "test-key"),
+							resource.TestCheckResourceAttr("jwk_okp_key.example", "alg", alg),
+						),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestOKPKey_Curves448(t *testing.T) {
+	os.Setenv("TF_ACC", "true")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_okp_key" "sig" {
+  kid = "sig-448"
+  use = "sig"
+  alg = "EdDSA"
+  crv = "Ed448"
+}
+
+resource "jwk_okp_key" "enc" {
+  kid = "enc-448"
+  use = "enc"
+  alg = "ECDH-ES"
+  crv = "X448"
+}
+
+resource "jwk_keyset" "example" {
+  keys = [
+    provider::jwk::public_key(jwk_okp_key.sig.json, "sig-448"),
+    provider::jwk::public_key(jwk_okp_key.enc.json, "enc-448"),
+  ]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_okp_key.sig", "crv", "Ed448"),
+					resource.TestCheckResourceAttr("jwk_okp_key.enc", "crv", "X448"),
+					resource.TestCheckResourceAttrWith("jwk_keyset.example", "json", func(value string) error {
+						if !containsSubstring(value, `"crv":"Ed448"`) || !containsSubstring(value, `"crv":"X448"`) {
+							return fmt.Errorf("keyset JSON doesn't contain Ed448 and X448 keys")
+						}
+						if containsSubstring(value, `"d":`) {
+							return fmt.Errorf("keyset JSON should only contain public keys")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestOKPKey_PublicKeyAndKeyset(t *testing.T) {
+	os.Setenv("TF_ACC", "true")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_okp_key" "sig" {
+  kid = "sig-1"
+  use = "sig"
+  alg = "EdDSA"
+  crv = "Ed25519"
+}
+
+resource "jwk_okp_key" "enc" {
+  kid = "enc-1"
+  use = "enc"
+  alg = "ECDH-ES"
+  crv = "X25519"
+}
+
+resource "jwk_keyset" "example" {
+  keys = [
+    jwk_okp_key.sig.json,
+    provider::jwk::public_key(jwk_okp_key.enc.json, "enc-1-public"),
+  ]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_keyset.example", "keys.#", "2"),
+					resource.TestCheckResourceAttrWith("jwk_keyset.example", "json", func(value string) error {
+						if !containsSubstring(value, `"kid":"sig-1"`) {
+							return fmt.Errorf("keyset JSON doesn't contain sig-1 key")
+						}
+						if !containsSubstring(value, `"kid":"enc-1-public"`) {
+							return fmt.Errorf("keyset JSON doesn't contain enc-1-public key")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestOKPKey_InvalidCurveForUse(t *testing.T) {
+	os.Setenv("TF_ACC", "true")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_okp_key" "example" {
+  kid = "test-key"
+  use = "sig"
+  alg = "EdDSA"
+  crv = "X25519"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid 'crv' attribute for use: 'sig'`),
+			},
+		},
+	})
+}
+
+func TestJwkOKPKeyResource_Import(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	testKey := `{
+        "kid": "imported-okp-key",
+        "kty": "OKP",
+        "use": "sig",
+        "alg": "EdDSA",
+        "crv": "Ed25519",
+        "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+    }`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `provider "jwk" {}
+				resource "jwk_okp_key" "test" {
+				# (resource arguments)
+				}`,
+				ImportState:                          true,
+				ImportStateId:                        testKey,
+				ImportStateVerify:                    false,
+				ImportStateVerifyIdentifierAttribute: "kid",
+				ResourceName:                         "jwk_okp_key.test",
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_okp_key.test", "kid", "imported-okp-key"),
+					resource.TestCheckResourceAttr("jwk_okp_key.test", "use", "sig"),
+					resource.TestCheckResourceAttr("jwk_okp_key.test", "crv", "Ed25519"),
+					resource.TestCheckResourceAttr("jwk_okp_key.test", "alg", "EdDSA"),
+					resource.TestCheckResourceAttrSet("jwk_okp_key.test", "json"),
+				),
+			},
+		},
+	})
+}
+
+// Public key of the imported key does not match its private key (RFC 8037, A.1 with altered 'x')
+func TestJwkOKPKeyResource_ImportMismatchedKey(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	testKey := `{
+        "kid": "tampered-okp-key",
+        "kty": "OKP",
+        "use": "sig",
+        "alg": "EdDSA",
+        "crv": "Ed25519",
+        "d": "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+        "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURA"
+    }`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `provider "jwk" {}
+				resource "jwk_okp_key" "test" {
+				# (resource arguments)
+				}`,
+				ImportState:   true,
+				ImportStateId: testKey,
+				ResourceName:  "jwk_okp_key.test",
+				ExpectError:   regexp.MustCompile(`OKP public key does not match the private key`),
+			},
+		},
+	})
+}
+
+func TestOKPKey_UUIDKID(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_okp_key" "example" {
+  use          = "sig"
+  crv          = "Ed25519"
+  kid_strategy = "uuid"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("jwk_okp_key.example", "kid",
+						regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestOKPKey_UpdateKeepsKey(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	var thumbprint string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_okp_key" "example" {
+  kid = "key-1"
+  use = "sig"
+  alg = "EdDSA"
+  crv = "Ed25519"
+}
+`,
+				Check: resource.TestCheckResourceAttrWith("jwk_okp_key.example", "thumbprint", func(value string) error {
+					thumbprint = value
+					return nil
+				}),
+			},
+			{
+				// Metadata changes update the key in place
+				Config: `
+resource "jwk_okp_key" "example" {
+  kid = "key-2"
+  use = "sig"
+  alg = "Ed25519"
+  crv = "Ed25519"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_okp_key.example", "kid", "key-2"),
+					resource.TestCheckResourceAttrWith("jwk_okp_key.example", "json", func(value string) error {
+						if !containsSubstring(value, `"kid":"key-2"`) {
+							return fmt.Errorf("key JSON doesn't contain the updated metadata")
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("jwk_okp_key.example", "thumbprint", func(value string) error {
+						if value != thumbprint {
+							return fmt.Errorf("key was regenerated: thumbprint changed from %s to %s", thumbprint, value)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				// A different curve replaces the key
+				Config: `
+resource "jwk_okp_key" "example" {
+  kid = "key-2"
+  use = "sig"
+  alg = "EdDSA"
+  crv = "Ed448"
+}
+`,
+				Check: resource.TestCheckResourceAttrWith("jwk_okp_key.example", "thumbprint", func(value string) error {
+					if value == thumbprint {
+						return fmt.Errorf("key was not regenerated for the new curve")
+					}
+					return nil
+				}),
+			},
+		},
+	})
+}
@@ -3,13 +3,16 @@ package provider_test
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"testing"
 
 	"terraform-provider-jwk/internal/provider"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func TestOctKey_Basic(t *testing.T) {
@@ -141,3 +144,172 @@ func TestJwkOctKeyResource_Import(t *testing.T) {
 		},
 	})
 }
+
+func TestOctKey_TimestampKID(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_oct_key" "example" {
+  kid          = "test-key"
+  kid_strategy = "timestamp"
+  use          = "sig"
+  size         = 256
+}
+`,
+				ExpectError: regexp.MustCompile(`Conflicting 'kid' and 'kid_strategy' attributes`),
+			},
+			{
+				Config: `
+resource "jwk_oct_key" "example" {
+  kid_strategy = "timestamp"
+  use          = "sig"
+  size         = 256
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("jwk_oct_key.example", "kid",
+						regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestOctKey_Ephemeral(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk":  providerserver.NewProtocol6WithError(provider.NewProvider()),
+			"echo": echoprovider.NewProviderServer(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+ephemeral "jwk_oct_key" "example" {
+  kid  = "hmac-1"
+  use  = "sig"
+  size = 256
+  alg  = "HS256"
+}
+
+provider "echo" {
+  data = ephemeral.jwk_oct_key.example
+}
+
+resource "echo" "example" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("echo.example", "data.kid", "hmac-1"),
+					resource.TestMatchResourceAttr("echo.example", "data.json", regexp.MustCompile(`"k":"[A-Za-z0-9_-]{43}"`)),
+				),
+			},
+		},
+	})
+}
+
+func TestOctKey_UpdateKeepsKey(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	var keyJSON string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_oct_key" "example" {
+  use  = "sig"
+  alg  = "HS256"
+  size = 256
+}
+`,
+				Check: resource.TestCheckResourceAttrWith("jwk_oct_key.example", "json", func(value string) error {
+					keyJSON = value
+					return nil
+				}),
+			},
+			{
+				// The generated Key ID and the key are kept, when only the metadata changes
+				Config: `
+resource "jwk_oct_key" "example" {
+  use  = "enc"
+  alg  = "A256KW"
+  size = 256
+}
+`,
+				Check: resource.TestCheckResourceAttrWith("jwk_oct_key.example", "json", func(value string) error {
+					key := regexp.MustCompile(`"k":"[^"]+"`).FindString(keyJSON)
+					kid := regexp.MustCompile(`"kid":"[^"]+"`).FindString(keyJSON)
+					if !containsSubstring(value, key) || !containsSubstring(value, kid) {
+						return fmt.Errorf("key was regenerated: %s", value)
+					}
+					if !containsSubstring(value, `"alg":"A256KW"`) || !containsSubstring(value, `"use":"enc"`) {
+						return fmt.Errorf("key JSON doesn't contain the updated metadata")
+					}
+					return nil
+				}),
+			},
+		},
+	})
+}
+
+func TestOctKey_Keepers(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	config := `
+resource "jwk_oct_key" "example" {
+  use  = "sig"
+  alg  = "HS256"
+  size = 256
+
+  keepers = {
+    environment = "%s"
+  }
+}
+`
+	var thumbprint string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "build-1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_oct_key.example", "keepers.environment", "build-1"),
+					resource.TestCheckResourceAttrWith("jwk_oct_key.example", "thumbprint", func(value string) error {
+						thumbprint = value
+						return nil
+					}),
+				),
+			},
+			{
+				// Changing 'keepers' creates a new key
+				Config: fmt.Sprintf(config, "build-2"),
+				Check: resource.TestCheckResourceAttrWith("jwk_oct_key.example", "thumbprint", func(value string) error {
+					if value == thumbprint {
+						return fmt.Errorf("key was not regenerated on change of keepers")
+					}
+					return nil
+				}),
+			},
+		},
+	})
+}
@@ -0,0 +1,174 @@
+package provider_test
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"terraform-provider-jwk/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestProviderDefaults(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "jwk" {
+  defaults {
+    use          = "sig"
+    algorithms   = { RSA = "PS256", EC = "ES384", oct = "HS512" }
+    rsa_size     = 3072
+    ec_curve     = "P-521"
+    kid_prefix   = "app"
+    kid_template = "$${prefix}-$${kty}-$${alg}"
+  }
+}
+
+resource "jwk_rsa_key" "rsa1" {
+}
+
+resource "jwk_ec_key" "ec1" {
+}
+
+resource "jwk_oct_key" "oct1" {
+  size = 512
+}
+
+resource "jwk_okp_key" "okp1" {
+  kid = "okp-1"
+  use = "enc"
+  crv = "X25519"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_rsa_key.rsa1", "use", "sig"),
+					resource.TestCheckResourceAttr("jwk_rsa_key.rsa1", "alg", "PS256"),
+					resource.TestCheckResourceAttr("jwk_rsa_key.rsa1", "size", "3072"),
+					resource.TestCheckResourceAttr("jwk_rsa_key.rsa1", "kid", "app-RSA-PS256"),
+					// The curve follows the default algorithm
+					resource.TestCheckResourceAttr("jwk_ec_key.ec1", "alg", "ES384"),
+					resource.TestCheckResourceAttr("jwk_ec_key.ec1", "crv", "P-384"),
+					resource.TestCheckResourceAttr("jwk_oct_key.oct1", "kid", "app-oct-HS512"),
+					// Attributes given in the resource take precedence, no 'OKP' algorithm is configured
+					resource.TestCheckResourceAttr("jwk_okp_key.okp1", "kid", "okp-1"),
+					resource.TestCheckResourceAttr("jwk_okp_key.okp1", "use", "enc"),
+					resource.TestCheckNoResourceAttr("jwk_okp_key.okp1", "alg"),
+				),
+			},
+		},
+	})
+}
+
+func TestProviderDefaults_Ephemeral(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk":  providerserver.NewProtocol6WithError(provider.NewProvider()),
+			"echo": echoprovider.NewProviderServer(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "jwk" {
+  defaults {
+    use          = "sig"
+    algorithms   = { RSA = "PS256", EC = "ES384", oct = "HS512" }
+    rsa_size     = 3072
+    kid_prefix   = "app"
+    kid_template = "$${prefix}-$${kty}-$${alg}"
+  }
+}
+
+ephemeral "jwk_rsa_key" "rsa1" {
+}
+
+ephemeral "jwk_ec_key" "ec1" {
+}
+
+ephemeral "jwk_oct_key" "oct1" {
+  size = 512
+}
+
+provider "echo" {
+  data = {
+    rsa1 = ephemeral.jwk_rsa_key.rsa1
+    ec1  = ephemeral.jwk_ec_key.ec1
+    oct1 = ephemeral.jwk_oct_key.oct1
+  }
+}
+
+resource "echo" "example" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("echo.example", "data.rsa1.use", "sig"),
+					resource.TestCheckResourceAttr("echo.example", "data.rsa1.alg", "PS256"),
+					resource.TestCheckResourceAttr("echo.example", "data.rsa1.size", "3072"),
+					resource.TestCheckResourceAttr("echo.example", "data.rsa1.kid", "app-RSA-PS256"),
+					// The curve follows the default algorithm
+					resource.TestCheckResourceAttr("echo.example", "data.ec1.alg", "ES384"),
+					resource.TestCheckResourceAttr("echo.example", "data.ec1.crv", "P-384"),
+					resource.TestCheckResourceAttr("echo.example", "data.oct1.kid", "app-oct-HS512"),
+				),
+			},
+		},
+	})
+}
+
+func TestProviderDefaults_Missing(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "jwk" {
+  defaults {
+    use = "sig"
+  }
+}
+
+resource "jwk_rsa_key" "rsa1" {
+  alg = "RS256"
+}
+`,
+				ExpectError: regexp.MustCompile(`Missing 'size' attribute`),
+			},
+			{
+				Config: `
+provider "jwk" {
+  defaults {
+    kid_template = "$${name}"
+  }
+}
+
+resource "jwk_oct_key" "oct1" {
+  use  = "sig"
+  size = 256
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid variable in 'kid_template'`),
+			},
+		},
+	})
+}
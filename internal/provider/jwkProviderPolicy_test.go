@@ -0,0 +1,258 @@
+package provider_test
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"testing"
+
+	"terraform-provider-jwk/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestProviderPolicy(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "jwk" {
+  policy = "nist-2030"
+}
+
+resource "jwk_rsa_key" "rsa1" {
+  use  = "sig"
+  alg  = "PS256"
+  size = 2048
+}
+`,
+				ExpectError: regexp.MustCompile(`Policy rule 'min-rsa-size' violated`),
+			},
+			{
+				Config: `
+provider "jwk" {
+  policy = "fips-140-3"
+}
+
+resource "jwk_oct_key" "oct1" {
+  use  = "sig"
+  alg  = "none"
+  size = 256
+}
+`,
+				ExpectError: regexp.MustCompile(`Policy rule 'denied-algorithm' violated`),
+			},
+			{
+				Config: `
+provider "jwk" {
+  policy = "nist-2030"
+}
+
+resource "jwk_oct_key" "oct1" {
+  use  = "sig"
+  size = 128
+}
+`,
+				ExpectError: regexp.MustCompile(`Policy rule 'min-hmac-size' violated`),
+			},
+			{
+				Config: `
+provider "jwk" {
+  policy = "nist-2030"
+}
+
+resource "jwk_rsa_key" "rsa1" {
+  use  = "sig"
+  alg  = "PS256"
+  size = 3072
+}
+
+resource "jwk_ec_key" "ec1" {
+  use = "sig"
+  alg = "ES256"
+  crv = "P-256"
+}
+
+resource "jwk_oct_key" "oct1" {
+  use  = "sig"
+  alg  = "HS256"
+  size = 256
+}
+
+resource "jwk_keyset" "keyset1" {
+  keys = [jwk_rsa_key.rsa1.json, jwk_ec_key.ec1.json]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_rsa_key.rsa1", "size", "3072"),
+					resource.TestCheckResourceAttrSet("jwk_keyset.keyset1", "json"),
+				),
+			},
+		},
+	})
+}
+
+func TestProviderPolicy_Custom(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "jwk" {
+  policy = "custom"
+}
+
+resource "jwk_okp_key" "okp1" {
+  use = "sig"
+  crv = "Ed25519"
+}
+`,
+				ExpectError: regexp.MustCompile(`Missing 'custom_policy'`),
+			},
+			{
+				Config: `
+provider "jwk" {
+  policy = "custom"
+
+  custom_policy {
+    denied_algorithms = ["RS257"]
+  }
+}
+
+resource "jwk_okp_key" "okp1" {
+  use = "sig"
+  crv = "Ed25519"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid algorithm in 'denied_algorithms'`),
+			},
+			{
+				Config: `
+provider "jwk" {
+  policy = "custom"
+
+  custom_policy {
+    denied_curves = ["Ed448"]
+  }
+}
+
+resource "jwk_okp_key" "okp1" {
+  use = "sig"
+  crv = "Ed448"
+}
+`,
+				ExpectError: regexp.MustCompile(`Policy rule 'denied-curve' violated`),
+			},
+			{
+				Config: `
+provider "jwk" {
+  policy = "custom"
+
+  custom_policy {
+    denied_curves = ["Ed448"]
+    min_rsa_size  = 4096
+  }
+}
+
+resource "jwk_okp_key" "okp1" {
+  use = "sig"
+  crv = "Ed25519"
+}
+
+resource "jwk_rotating_key" "rotating1" {
+  kid_prefix      = "sign"
+  kty             = "RSA"
+  use             = "sig"
+  alg             = "RS256"
+  rotation_period = "30d"
+}
+`,
+				ExpectError: regexp.MustCompile(`Policy rule 'min-rsa-size' violated`),
+			},
+		},
+	})
+}
+
+func TestProviderPolicy_Functions(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+	t.Setenv("JWK_POLICY", "nist-2030")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "token" {
+  value = provider::jwk::sign_jwt(
+    jsonencode({ kty = "oct", k = "AAAAAAAAAAAAAAAAAAAAAA", alg = "HS256" }),
+    jsonencode({ sub = "service" }),
+    ""
+  )
+}
+`,
+				ExpectError: regexp.MustCompile(`rule 'min-hmac-size' of policy 'nist-2030'`),
+			},
+			{
+				// The provider uses the policy of the environment, when 'policy' is not given
+				Config: `
+resource "jwk_rsa_key" "rsa1" {
+  use  = "enc"
+  alg  = "RSA1_5"
+  size = 3072
+}
+`,
+				ExpectError: regexp.MustCompile(`Policy rule 'denied-algorithm' violated`),
+			},
+		},
+	})
+}
+
+func TestProviderPolicy_FunctionsWarning(t *testing.T) {
+	server, err := providerserver.NewProtocol6WithError(provider.NewProvider())()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := map[string]tftypes.Value{"policy": tftypes.NewValue(tftypes.String, "nist-2030")}
+
+	// Functions are not protected by the policy of the provider
+	t.Setenv("JWK_POLICY", "")
+	diags := configureTestProvider(t, server, schemaResp.Provider, policy)
+	if len(diags) != 1 || diags[0].Severity != tfprotov6.DiagnosticSeverityWarning || !hasDiagnostic(diags, "not protected by policy 'nist-2030'") {
+		t.Fatalf("expected warning on unprotected functions, got %v", diags)
+	}
+	if !diags[0].Attribute.Equal(tftypes.NewAttributePath().WithAttributeName("policy")) {
+		t.Fatalf("expected warning on 'policy', got %s", diags[0].Attribute)
+	}
+
+	t.Setenv("JWK_POLICY", "fips-140-3")
+	if diags := configureTestProvider(t, server, schemaResp.Provider, policy); !hasDiagnostic(diags, "Set JWK_POLICY=nist-2030") {
+		t.Fatalf("expected warning on differing JWK_POLICY, got %v", diags)
+	}
+
+	// Same policy applies to the functions
+	t.Setenv("JWK_POLICY", "nist-2030")
+	if diags := configureTestProvider(t, server, schemaResp.Provider, policy); len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+}
@@ -0,0 +1,212 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"terraform-provider-jwk/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Provider server for reading and planning resources with given state, e.g. with a tampered key,
+// which can not be produced by Terraform itself
+type stateTestServer struct {
+	t       *testing.T
+	server  tfprotov6.ProviderServer
+	schemas map[string]*tfprotov6.Schema
+}
+
+func newStateTestServer(t *testing.T) *stateTestServer {
+	ctx := context.Background()
+
+	server, err := providerserver.NewProtocol6WithError(provider.NewProvider())()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Provider without configuration
+	for _, d := range configureTestProvider(t, server, schemaResp.Provider, nil) {
+		t.Fatalf("configure provider: %s: %s", d.Summary, d.Detail)
+	}
+
+	return &stateTestServer{t: t, server: server, schemas: schemaResp.ResourceSchemas}
+}
+
+// Configure the provider with given attributes, attributes not given are null. Returns the diagnostics of Configure.
+func configureTestProvider(t *testing.T, server tfprotov6.ProviderServer, schema *tfprotov6.Schema, values map[string]tftypes.Value) []*tfprotov6.Diagnostic {
+	providerType := schema.ValueType().(tftypes.Object)
+	providerAttributes := make(map[string]tftypes.Value, len(providerType.AttributeTypes))
+	for name, attributeType := range providerType.AttributeTypes {
+		providerAttributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range values {
+		providerAttributes[name] = value
+	}
+
+	config, err := tfprotov6.NewDynamicValue(providerType, tftypes.NewValue(providerType, providerAttributes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.ConfigureProvider(context.Background(), &tfprotov6.ConfigureProviderRequest{Config: &config})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Diagnostics
+}
+
+// Object value of the resource, attributes not given are null
+func (s *stateTestServer) value(typeName string, values map[string]tftypes.Value) tftypes.Value {
+	objectType := s.schemas[typeName].ValueType().(tftypes.Object)
+
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range values {
+		attributes[name] = value
+	}
+	return tftypes.NewValue(objectType, attributes)
+}
+
+func (s *stateTestServer) dynamicValue(typeName string, value tftypes.Value) *tfprotov6.DynamicValue {
+	dynamicValue, err := tfprotov6.NewDynamicValue(s.schemas[typeName].ValueType(), value)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return &dynamicValue
+}
+
+func (s *stateTestServer) unmarshal(typeName string, dynamicValue *tfprotov6.DynamicValue) map[string]tftypes.Value {
+	value, err := dynamicValue.Unmarshal(s.schemas[typeName].ValueType())
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	var values map[string]tftypes.Value
+	if err := value.As(&values); err != nil {
+		s.t.Fatal(err)
+	}
+	return values
+}
+
+// Read the resource with given state. Returns the new state, or the diagnostics of Read.
+func (s *stateTestServer) read(typeName string, state map[string]tftypes.Value) (map[string]tftypes.Value, []*tfprotov6.Diagnostic) {
+	resp, err := s.server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: s.dynamicValue(typeName, s.value(typeName, state)),
+	})
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		return nil, resp.Diagnostics
+	}
+	return s.unmarshal(typeName, resp.NewState), nil
+}
+
+// Plan the resource with given configuration and prior state. Returns whether the plan has changes.
+func (s *stateTestServer) planHasChanges(typeName string, config map[string]tftypes.Value, prior map[string]tftypes.Value) bool {
+	configValue := s.value(typeName, config)
+	priorValue := s.value(typeName, prior)
+
+	var configValues, priorValues map[string]tftypes.Value
+	if err := configValue.As(&configValues); err != nil {
+		s.t.Fatal(err)
+	}
+	if err := priorValue.As(&priorValues); err != nil {
+		s.t.Fatal(err)
+	}
+
+	// Proposed new state takes the configured values, and the computed values of prior state
+	proposed := make(map[string]tftypes.Value, len(priorValues))
+	for name, value := range priorValues {
+		proposed[name] = value
+	}
+	for _, attribute := range s.schemas[typeName].Block.Attributes {
+		if !attribute.Computed || !configValues[attribute.Name].IsNull() {
+			proposed[attribute.Name] = configValues[attribute.Name]
+		}
+	}
+	for _, block := range s.schemas[typeName].Block.BlockTypes {
+		proposed[block.TypeName] = configValues[block.TypeName]
+	}
+
+	resp, err := s.server.PlanResourceChange(context.Background(), &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		Config:           s.dynamicValue(typeName, configValue),
+		PriorState:       s.dynamicValue(typeName, priorValue),
+		ProposedNewState: s.dynamicValue(typeName, s.value(typeName, proposed)),
+	})
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			s.t.Fatalf("plan: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	planned, err := resp.PlannedState.Unmarshal(s.schemas[typeName].ValueType())
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return !planned.Equal(priorValue)
+}
+
+// Json of a generated key with given parameters, e.g. as stored in state
+func testKeyJSON(t *testing.T, raw any, kid, use, alg string) string {
+	key, err := jwk.FromRaw(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range map[string]string{jwk.KeyIDKey: kid, jwk.KeyUsageKey: use, jwk.AlgorithmKey: alg} {
+		if err := key.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(keyJSON)
+}
+
+// Json of the key with members replaced, e.g. to tamper with the key material. Null values remove the member.
+func withJWKMembers(t *testing.T, keyJSON string, members map[string]any) string {
+	var values map[string]any
+	if err := json.Unmarshal([]byte(keyJSON), &values); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range members {
+		if value == nil {
+			delete(values, name)
+		} else {
+			values[name] = value
+		}
+	}
+	result, err := json.Marshal(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(result)
+}
+
+// Whether any of the diagnostics contains the text in its summary or detail
+func hasDiagnostic(diags []*tfprotov6.Diagnostic, text string) bool {
+	for _, d := range diags {
+		if strings.Contains(d.Summary, text) || strings.Contains(d.Detail, text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package provider_test
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"testing"
+
+	"terraform-provider-jwk/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const testRemoteJWKS = `{"keys":[
+  {"kty":"OKP","crv":"Ed25519","kid":"remote-sig-1","use":"sig","alg":"EdDSA","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+  {"kty":"EC","crv":"P-256","kid":"remote-sig-2","use":"sig","alg":"ES256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}
+]}`
+
+// Serves given JWKS, when request contains the expected authorization header
+func newJWKSHandler(jwks string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(jwks))
+	})
+}
+
+func TestRemoteKeyset_Basic(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	server := httptest.NewServer(newJWKSHandler(testRemoteJWKS))
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "jwk_remote_keyset" "partner" {
+  url     = "%s/jwks.json"
+  timeout = "5s"
+  headers = {
+    Authorization = "Bearer test-token"
+  }
+}
+`, server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jwk_remote_keyset.partner", "kids.#", "2"),
+					resource.TestCheckResourceAttr("data.jwk_remote_keyset.partner", "kids.0", "remote-sig-1"),
+					resource.TestCheckResourceAttr("data.jwk_remote_keyset.partner", "kids.1", "remote-sig-2"),
+					resource.TestCheckResourceAttr("data.jwk_remote_keyset.partner", "keys.0.kty", "OKP"),
+					resource.TestCheckResourceAttr("data.jwk_remote_keyset.partner", "keys.0.crv", "Ed25519"),
+					resource.TestCheckResourceAttr("data.jwk_remote_keyset.partner", "keys.1.alg", "ES256"),
+					resource.TestCheckResourceAttrWith("data.jwk_remote_keyset.partner", "json", func(value string) error {
+						if !containsSubstring(value, `"kid":"remote-sig-2"`) {
+							return fmt.Errorf("keyset JSON doesn't contain remote-sig-2 key")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestRemoteKeyset_CABundle(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	server := httptest.NewTLSServer(newJWKSHandler(testRemoteJWKS))
+	defer server.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "jwk_remote_keyset" "partner" {
+  url       = "%s/jwks.json"
+  ca_bundle = <<EOT
+%sEOT
+  headers = {
+    Authorization = "Bearer test-token"
+  }
+}
+
+resource "jwk_oct_key" "own" {
+  kid  = "own-1"
+  use  = "sig"
+  size = 256
+}
+
+resource "jwk_keyset" "combined" {
+  keys = concat(
+    [jwk_oct_key.own.json],
+    [for key in data.jwk_remote_keyset.partner.keys : key.json],
+  )
+}
+`, server.URL, caBundle),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.jwk_remote_keyset.partner", "kids.#", "2"),
+					resource.TestCheckResourceAttr("jwk_keyset.combined", "keys.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestRemoteKeyset_Errors(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	server := httptest.NewServer(newJWKSHandler(`{"keys":[{"kty":"RSA","kid":"broken","e":"AQAB"}]}`))
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "jwk_remote_keyset" "partner" {
+  url = "%s/jwks.json"
+}
+`, server.URL),
+				ExpectError: regexp.MustCompile(`unexpected status 401`),
+			},
+			{
+				Config: fmt.Sprintf(`
+data "jwk_remote_keyset" "partner" {
+  url = "%s/jwks.json"
+  headers = {
+    Authorization = "Bearer test-token"
+  }
+}
+`, server.URL),
+				ExpectError: regexp.MustCompile(`Invalid JWK Set`),
+			},
+		},
+	})
+}
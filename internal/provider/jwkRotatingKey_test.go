@@ -0,0 +1,205 @@
+package provider_test
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"terraform-provider-jwk/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestRotatingKey_Rotation(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	config := `
+resource "jwk_rotating_key" "signing" {
+  kid_prefix      = "sign"
+  kty             = "EC"
+  use             = "sig"
+  alg             = "%s"
+  rotation_period = "90d"
+  keep_previous   = %d
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "ES256", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "generation", "1"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "current_kid", "sign-1"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "keys.#", "1"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "previous_json.#", "0"),
+					resource.TestCheckResourceAttrSet("jwk_rotating_key.signing", "keys.0.created"),
+					resource.TestCheckResourceAttrSet("jwk_rotating_key.signing", "next_rotation"),
+				),
+			},
+			{
+				// Changing the key specification rotates the key
+				Config: fmt.Sprintf(config, "ES384", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "generation", "2"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "current_kid", "sign-2"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "keys.#", "2"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "keys.1.kid", "sign-1"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "previous_json.#", "1"),
+					resource.TestCheckResourceAttrWith("jwk_rotating_key.signing", "public_json", func(value string) error {
+						if !containsSubstring(value, `"kid":"sign-1"`) || !containsSubstring(value, `"kid":"sign-2"`) {
+							return fmt.Errorf("public keyset JSON doesn't contain both keys")
+						}
+						if containsSubstring(value, `"d":`) {
+							return fmt.Errorf("public keyset JSON contains private key material")
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				// Only 'keep_previous' keys are kept
+				Config: fmt.Sprintf(config, "ES512", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "current_kid", "sign-3"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "keys.#", "2"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "keys.1.kid", "sign-2"),
+				),
+			},
+			{
+				// Reducing kept keys does not rotate
+				Config: fmt.Sprintf(config, "ES512", 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "current_kid", "sign-3"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "keys.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestRotatingKey_InvalidConfig(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "jwk_rotating_key" "signing" {
+  kid_prefix      = "sign"
+  kty             = "RSA"
+  use             = "sig"
+  rotation_period = "three months"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid 'rotation_period' attribute`),
+			},
+			{
+				Config: `
+resource "jwk_rotating_key" "signing" {
+  kid_prefix      = "sign"
+  kty             = "RSA"
+  use             = "sig"
+  alg             = "ES256"
+  rotation_period = "90d"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid 'alg' attribute`),
+			},
+		},
+	})
+}
+
+func TestRotatingKey_Keepers(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	config := `
+resource "jwk_rotating_key" "signing" {
+  kid_prefix      = "sign"
+  kty             = "OKP"
+  use             = "sig"
+  rotation_period = "90d"
+
+  keepers = {
+    incident = "%s"
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "INC-1"),
+				Check:  resource.TestCheckResourceAttr("jwk_rotating_key.signing", "current_kid", "sign-1"),
+			},
+			{
+				// Changing 'keepers' rotates the key, keeping the previous one
+				Config: fmt.Sprintf(config, "INC-2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "current_kid", "sign-2"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "keys.1.kid", "sign-1"),
+				),
+			},
+		},
+	})
+}
+
+func TestRotatingKey_UnknownSettings(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	// 'keep_previous' and 'rotation_period' are unknown during plan, when the oct key is replaced
+	config := `
+resource "jwk_oct_key" "settings" {
+  use  = "sig"
+  size = 256
+
+  keepers = {
+    version = "%s"
+  }
+}
+
+resource "jwk_rotating_key" "signing" {
+  kid_prefix      = "sign"
+  kty             = "OKP"
+  use             = "sig"
+  rotation_period = jwk_oct_key.settings.thumbprint != "" ? "90d" : "30d"
+  keep_previous   = jwk_oct_key.settings.thumbprint != "" ? 1 : 0
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "1"),
+				Check:  resource.TestCheckResourceAttr("jwk_rotating_key.signing", "current_kid", "sign-1"),
+			},
+			{
+				// Unknown rotation settings do not rotate the key
+				Config: fmt.Sprintf(config, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "generation", "1"),
+					resource.TestCheckResourceAttr("jwk_rotating_key.signing", "current_kid", "sign-1"),
+				),
+			},
+		},
+	})
+}
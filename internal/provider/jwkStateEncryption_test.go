@@ -0,0 +1,200 @@
+package provider_test
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"terraform-provider-jwk/internal/provider"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// Compact JWE: header, encrypted key, iv, ciphertext and tag
+var compactJWE = regexp.MustCompile(`^[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+$`)
+
+func TestStateEncryption_Passphrase(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "jwk" {
+  state_encryption {
+    passphrase = "correct horse battery staple"
+  }
+}
+
+resource "jwk_rsa_key" "rsa1" {
+  kid  = "rsa1"
+  use  = "sig"
+  size = 2048
+  alg  = "RS256"
+}
+
+resource "jwk_oct_key" "oct1" {
+  kid  = "oct1"
+  use  = "sig"
+  size = 256
+  alg  = "HS256"
+}
+
+resource "jwk_keyset" "example" {
+  keys = [
+    jwk_rsa_key.rsa1.json,
+    jwk_oct_key.oct1.json,
+  ]
+}
+
+output "rsa_kid" {
+  value = jsondecode(provider::jwk::decrypt_state(jwk_rsa_key.rsa1.json, "correct horse battery staple")).kid
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("jwk_rsa_key.rsa1", "json", compactJWE),
+					resource.TestCheckNoResourceAttr("jwk_rsa_key.rsa1", "private_key_pem"),
+					resource.TestCheckNoResourceAttr("jwk_rsa_key.rsa1", "private_key_pem_pkcs8"),
+					resource.TestCheckResourceAttrSet("jwk_rsa_key.rsa1", "public_key_pem"),
+					resource.TestMatchResourceAttr("jwk_oct_key.oct1", "json", compactJWE),
+					resource.TestMatchResourceAttr("jwk_keyset.example", "json", compactJWE),
+					resource.TestMatchResourceAttr("jwk_keyset.example", "public_json", regexp.MustCompile(`"kid":"rsa1"`)),
+					resource.TestCheckOutput("rsa_kid", "rsa1"),
+				),
+			},
+		},
+	})
+}
+
+func TestStateEncryption_KEK(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+locals {
+  kek = jsonencode({
+    kty = "oct"
+    alg = "A256KW"
+    k   = "GawgguFyGrWKav7AX4VKUg1q1nL4dgWnIN1EFuuAPuI"
+  })
+}
+
+provider "jwk" {
+  state_encryption {
+    key = local.kek
+  }
+}
+
+resource "jwk_ec_key" "ec1" {
+  kid = "ec1"
+  use = "sig"
+  crv = "P-256"
+  alg = "ES256"
+}
+
+output "ec_kid" {
+  value = jsondecode(provider::jwk::decrypt_state(jwk_ec_key.ec1.json, local.kek)).kid
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("jwk_ec_key.ec1", "json", compactJWE),
+					resource.TestCheckNoResourceAttr("jwk_ec_key.ec1", "private_key_pem"),
+					resource.TestCheckOutput("ec_kid", "ec1"),
+				),
+			},
+		},
+	})
+}
+
+func TestStateEncryption_RotatingKey(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	config := `
+provider "jwk" {
+  state_encryption {
+    passphrase = "correct horse battery staple"
+  }
+}
+
+resource "jwk_rotating_key" "signing" {
+  kid_prefix      = "sign"
+  kty             = "EC"
+  use             = "sig"
+  alg             = "%s"
+  rotation_period = "90d"
+}
+
+output "current_kid" {
+  value = jsondecode(provider::jwk::decrypt_state(jwk_rotating_key.signing.current_json, "correct horse battery staple")).kid
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "ES256"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("jwk_rotating_key.signing", "current_json", compactJWE),
+					resource.TestMatchResourceAttr("jwk_rotating_key.signing", "keys.0.json", compactJWE),
+					resource.TestMatchResourceAttr("jwk_rotating_key.signing", "json", compactJWE),
+					resource.TestCheckOutput("current_kid", "sign-1"),
+				),
+			},
+			{
+				// Previous keys stay encrypted after rotation
+				Config: fmt.Sprintf(config, "ES384"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("jwk_rotating_key.signing", "current_json", compactJWE),
+					resource.TestMatchResourceAttr("jwk_rotating_key.signing", "previous_json.0", compactJWE),
+					resource.TestMatchResourceAttr("jwk_rotating_key.signing", "public_json", regexp.MustCompile(`"kid":"sign-1"`)),
+					resource.TestCheckOutput("current_kid", "sign-2"),
+				),
+			},
+		},
+	})
+}
+
+func TestStateEncryption_Invalid(t *testing.T) {
+	os.Setenv("TF_ACC", "1")
+	defer os.Unsetenv("TF_ACC")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"jwk": providerserver.NewProtocol6WithError(provider.NewProvider()),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "jwk" {
+  state_encryption {
+    passphrase = "too short"
+  }
+}
+
+resource "jwk_oct_key" "oct1" {
+  use  = "sig"
+  size = 256
+}
+`,
+				ExpectError: regexp.MustCompile(`passphrase must be at least 16 characters long`),
+			},
+		},
+	})
+}
@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+)
+
+// Claims, which accept times relative to the signing time
+var relativeTimeClaims = []string{"exp", "iat", "nbf"}
+
+// Parse a JSON object, keeping numbers as they are
+func parseJSONObject(value string) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+
+	if strings.TrimSpace(value) == "" {
+		return result, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("expected a JSON object: %w", err)
+	}
+	if result == nil {
+		return nil, fmt.Errorf("expected a JSON object, got null")
+	}
+
+	return result, nil
+}
+
+// Resolve a relative time, 'now', 'now+<period>' or 'now-<period>', to seconds since epoch
+func relativeTime(value string, now time.Time) (int64, error) {
+	rest, ok := strings.CutPrefix(value, "now")
+	if !ok {
+		return 0, fmt.Errorf("expected seconds since epoch, 'now', 'now+<duration>' or 'now-<duration>', got '%s'", value)
+	}
+	if rest == "" {
+		return now.Unix(), nil
+	}
+
+	sign := time.Duration(1)
+	if period, ok := strings.CutPrefix(rest, "-"); ok {
+		sign, rest = -1, period
+	} else if period, ok := strings.CutPrefix(rest, "+"); ok {
+		rest = period
+	} else {
+		return 0, fmt.Errorf("expected seconds since epoch, 'now', 'now+<duration>' or 'now-<duration>', got '%s'", value)
+	}
+
+	period, err := parsePeriod(rest)
+	if err != nil {
+		return 0, err
+	}
+	return now.Add(sign * period).Unix(), nil
+}
+
+// Sign the claims with the private key as a compact JWS. The algorithm is taken from
+// 'alg' of the headers or of the key, and 'kid' from the key. Relative times of
+// 'exp', 'iat' and 'nbf' are resolved against the current time.
+func signJWT(key jwk.Key, claims map[string]interface{}, headers map[string]interface{}) (string, error) {
+	if key.KeyUsage() == "enc" {
+		return "", fmt.Errorf("key '%s' is an encryption key (use 'enc') and cannot be used for signing", key.KeyID())
+	}
+	if !isPrivateJWK(key) {
+		return "", fmt.Errorf("a private key is required for signing")
+	}
+
+	// Algorithm of the headers overrides the one of the key
+	alg := key.Algorithm().String()
+	if value, ok := headers[jws.AlgorithmKey]; ok {
+		s, isString := value.(string)
+		if !isString {
+			return "", fmt.Errorf("header 'alg' must be a string")
+		}
+		alg = s
+		delete(headers, jws.AlgorithmKey)
+	}
+	if alg == "" {
+		return "", fmt.Errorf("key has no 'alg', give the algorithm in header 'alg'")
+	}
+	var signatureAlgorithm jwa.SignatureAlgorithm
+	if err := signatureAlgorithm.Accept(alg); err != nil {
+		return "", fmt.Errorf("unsupported signature algorithm '%s'", alg)
+	}
+
+	if value, ok := headers[jws.KeyIDKey]; ok && key.KeyID() != "" && value != key.KeyID() {
+		return "", fmt.Errorf("header 'kid' conflicts with Key ID '%s' of the key", key.KeyID())
+	}
+
+	now := time.Now()
+	for _, claim := range relativeTimeClaims {
+		if value, ok := claims[claim].(string); ok {
+			seconds, err := relativeTime(value, now)
+			if err != nil {
+				return "", fmt.Errorf("invalid claim '%s': %w", claim, err)
+			}
+			claims[claim] = seconds
+		}
+	}
+
+	var payload bytes.Buffer
+	encoder := json.NewEncoder(&payload)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(claims); err != nil {
+		return "", fmt.Errorf("failed to serialize claims: %w", err)
+	}
+
+	protected := jws.NewHeaders()
+	if err := protected.Set(jws.TypeKey, "JWT"); err != nil {
+		return "", err
+	}
+	for name, value := range headers {
+		if err := protected.Set(name, value); err != nil {
+			return "", fmt.Errorf("invalid header '%s': %w", name, err)
+		}
+	}
+
+	token, err := jws.Sign(bytes.TrimRight(payload.Bytes(), "\n"), jws.WithKey(signatureAlgorithm, key, jws.WithProtectedHeaders(protected)))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign: %w", err)
+	}
+
+	return string(token), nil
+}
+
+// Verify the signature of a JWS with the keys of the key set. The key is selected by
+// 'kid' of the token, or the only key of the set is used, when the token has no 'kid'.
+// Returns the protected header and the payload.
+func verifyJWS(keyset jwk.Set, token string) (string, string, error) {
+	var message jws.Message
+	payload, err := jws.Verify(
+		[]byte(strings.TrimSpace(token)),
+		jws.WithKeySet(keyset, jws.WithInferAlgorithmFromKey(true), jws.WithUseDefault(true)),
+		jws.WithMessage(&message),
+	)
+	if err != nil {
+		return "", "", err
+	}
+
+	header, err := json.Marshal(message.Signatures()[0].ProtectedHeaders())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to serialize header: %w", err)
+	}
+
+	return string(header), string(payload), nil
+}
+
+// Check the algorithm of a verified JWS and the key it was verified with against the policy.
+// The key is selected like in verifyJWS, by 'kid' of the header or as the only key of the set.
+func checkJWSPolicy(policy *keyPolicy, keyset jwk.Set, header string) error {
+	if policy == nil {
+		return nil
+	}
+
+	var protected struct {
+		Alg string `json:"alg"`
+		KID string `json:"kid"`
+	}
+	if err := json.Unmarshal([]byte(header), &protected); err != nil {
+		return fmt.Errorf("failed to parse header: %w", err)
+	}
+
+	key, ok := keyset.LookupKeyID(protected.KID)
+	if protected.KID == "" && keyset.Len() == 1 {
+		key, ok = keyset.Key(0)
+	}
+	if !ok {
+		return nil
+	}
+
+	return policy.checkJWK(key, protected.Alg)
+}
+
+// Decode a compact JWT without verifying its signature. Returns the protected header and the claims.
+func decodeJWT(token string) (string, string, error) {
+	message, err := jws.Parse([]byte(strings.TrimSpace(token)), jws.WithCompact())
+	if err != nil {
+		return "", "", err
+	}
+
+	header, err := json.Marshal(message.Signatures()[0].ProtectedHeaders())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to serialize header: %w", err)
+	}
+
+	if _, err := parseJSONObject(string(message.Payload())); err != nil {
+		return "", "", fmt.Errorf("invalid claims: %w", err)
+	}
+
+	return string(header), string(message.Payload()), nil
+}
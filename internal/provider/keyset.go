@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Order of the keys in a key set
+const (
+	keysetSortInput = "input"
+	keysetSortKID   = "kid"
+)
+
+var validKeysetSorts = []string{keysetSortInput, keysetSortKID}
+
+// Selects the keys of a key set by their parameters. Keys lacking a filtered parameter do not match.
+type keysetFilterModel struct {
+	Use        types.String `tfsdk:"use"`
+	Algorithms types.List   `tfsdk:"alg"`
+	KeyTypes   types.List   `tfsdk:"kty"`
+}
+
+// A key of a key set with its Json representation and the attribute, it is given in
+type keysetKey struct {
+	json string
+	key  jwk.Key
+	at   path.Path
+}
+
+// How the keys of a key set are selected and ordered
+type keysetSelection struct {
+	use         string
+	algorithms  []string
+	keyTypes    []string
+	sort        string
+	deduplicate bool
+}
+
+// Selection of the key set configuration
+func newKeysetSelection(ctx context.Context, model KeysetModel) (keysetSelection, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	selection := keysetSelection{
+		sort:        model.Sort.ValueString(),
+		deduplicate: model.Deduplicate.ValueBool(),
+	}
+
+	if model.Filter != nil {
+		selection.use = model.Filter.Use.ValueString()
+		if !model.Filter.Algorithms.IsNull() {
+			diags.Append(model.Filter.Algorithms.ElementsAs(ctx, &selection.algorithms, false)...)
+		}
+		if !model.Filter.KeyTypes.IsNull() {
+			diags.Append(model.Filter.KeyTypes.ElementsAs(ctx, &selection.keyTypes, false)...)
+		}
+	}
+
+	return selection, diags
+}
+
+// Whether the key matches the filter
+func (s keysetSelection) matches(key jwk.Key) bool {
+	if s.use != "" && key.KeyUsage() != s.use {
+		return false
+	}
+	if s.algorithms != nil && !isValid(key.Algorithm().String(), s.algorithms) {
+		return false
+	}
+	if s.keyTypes != nil && !isValid(key.KeyType().String(), s.keyTypes) {
+		return false
+	}
+	return true
+}
+
+// Filter, de-duplicate and sort the keys. Of keys with the same thumbprint, the first one is kept.
+func (s keysetSelection) apply(keys []keysetKey) ([]keysetKey, error) {
+	selected := make([]keysetKey, 0, len(keys))
+	thumbprints := make(map[string]bool)
+
+	for _, key := range keys {
+		if !s.matches(key.key) {
+			continue
+		}
+
+		if s.deduplicate {
+			thumbprint, err := jwkThumbprint(key.key, defaultThumbprintHash)
+			if err != nil {
+				return nil, fmt.Errorf("key '%s': %w", key.key.KeyID(), err)
+			}
+			if thumbprints[thumbprint] {
+				continue
+			}
+			thumbprints[thumbprint] = true
+		}
+
+		selected = append(selected, key)
+	}
+
+	if s.sort == keysetSortKID {
+		sort.SliceStable(selected, func(i, j int) bool {
+			return selected[i].key.KeyID() < selected[j].key.KeyID()
+		})
+	}
+
+	return selected, nil
+}
+
+// Keys of 'keys' and of the key sets in 'source_keysets', which may be encrypted by state encryption.
+// Unknown values are skipped, as they are only given during plan.
+func (e *stateEncryption) openKeysetKeys(model KeysetModel) ([]keysetKey, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var keys []keysetKey
+
+	for i, value := range model.Keys.Elements() {
+		keyStr, ok := value.(types.String)
+		if !ok || keyStr.IsUnknown() || keyStr.IsNull() {
+			continue
+		}
+
+		at := path.Root("keys").AtListIndex(i)
+		keyJSON, err := e.open(keyStr.ValueString())
+		if err != nil {
+			diags.AddAttributeError(at, "Failed to decrypt key", err.Error())
+			continue
+		}
+		key, err := json2jwk(keyJSON)
+		if err != nil {
+			diags.AddAttributeError(at, "Invalid key", err.Error())
+			continue
+		}
+
+		keys = append(keys, keysetKey{json: keyJSON, key: key, at: at})
+	}
+
+	for i, value := range model.SourceKeysets.Elements() {
+		keysetStr, ok := value.(types.String)
+		if !ok || keysetStr.IsUnknown() || keysetStr.IsNull() {
+			continue
+		}
+
+		at := path.Root("source_keysets").AtListIndex(i)
+		keysetJSON, err := e.open(keysetStr.ValueString())
+		if err != nil {
+			diags.AddAttributeError(at, "Failed to decrypt key set", err.Error())
+			continue
+		}
+		parsed, raws, err := parseJWKSet([]byte(keysetJSON))
+		if err != nil {
+			diags.AddAttributeError(at, "Invalid key set", err.Error())
+			continue
+		}
+
+		for j, key := range parsed {
+			keys = append(keys, keysetKey{json: string(raws[j]), key: key, at: at})
+		}
+	}
+
+	return keys, diags
+}
+
+// Json representations of the keys as list, as taken by createJWKKeyset
+func keysetKeysList(keys []keysetKey) types.List {
+	values := make([]attr.Value, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, types.StringValue(key.json))
+	}
+	return types.ListValueMust(types.StringType, values)
+}
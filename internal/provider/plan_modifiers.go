@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Arbitrary values, which replace the key when changed, like 'keepers' of the random provider
+func keepersAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		Optional:    true,
+		ElementType: types.StringType,
+		Description: "Arbitrary map of values that, when changed, will trigger recreation of the key. " +
+			"The values are not used otherwise, e.g. an incident ID or the ID of a rebuilt environment.",
+		PlanModifiers: []planmodifier.Map{mapplanmodifier.RequiresReplace()},
+	}
+}
+
+// Keeps the Key ID of the state, when 'kid' is not configured and 'kid_strategy' is
+// unchanged, so that changes of other attributes do not generate a new Key ID.
+type kidPlanModifier struct{}
+
+func (m kidPlanModifier) Description(_ context.Context) string {
+	return "The generated Key ID is kept, unless `kid_strategy` changes."
+}
+
+func (m kidPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m kidPlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.State.Raw.IsNull() || req.StateValue.IsNull() || !req.ConfigValue.IsNull() || !req.PlanValue.IsUnknown() {
+		return
+	}
+
+	unchanged, diags := attributesUnchanged(ctx, req.Plan.GetAttribute, req.State.GetAttribute, "kid_strategy")
+	resp.Diagnostics.Append(diags...)
+	if unchanged {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// Keeps the value of a computed attribute of the state, when the attributes it is
+// derived from are unchanged. Unlike UseStateForUnknown, the value is planned as
+// unknown, when any of them changes.
+type useStateWhenUnchangedModifier struct {
+	attributes []string
+}
+
+// Plan modifier, which keeps the value of the state, when the given attributes are unchanged
+func useStateWhenUnchanged(attributes ...string) planmodifier.String {
+	return useStateWhenUnchangedModifier{attributes: attributes}
+}
+
+func (m useStateWhenUnchangedModifier) Description(_ context.Context) string {
+	return fmt.Sprintf("The value is kept, unless `%s` changes.", strings.Join(m.attributes, "`, `"))
+}
+
+func (m useStateWhenUnchangedModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m useStateWhenUnchangedModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || !req.PlanValue.IsUnknown() {
+		return
+	}
+
+	unchanged, diags := attributesUnchanged(ctx, req.Plan.GetAttribute, req.State.GetAttribute, m.attributes...)
+	resp.Diagnostics.Append(diags...)
+	if unchanged {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// Gets the value of an attribute, e.g. from plan or state
+type getAttributeFunc func(ctx context.Context, path path.Path, target interface{}) diag.Diagnostics
+
+// Check, whether the planned values of the attributes are equal to the values of the state.
+// Unknown planned values are considered as changed.
+func attributesUnchanged(ctx context.Context, planned, current getAttributeFunc, attributes ...string) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	for _, name := range attributes {
+		var plannedValue, currentValue attr.Value
+
+		diags.Append(planned(ctx, path.Root(name), &plannedValue)...)
+		diags.Append(current(ctx, path.Root(name), &currentValue)...)
+		if diags.HasError() {
+			return false, diags
+		}
+
+		if plannedValue.IsUnknown() || !plannedValue.Equal(currentValue) {
+			return false, diags
+		}
+	}
+
+	return true, diags
+}
@@ -0,0 +1,381 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Cryptographic policies of the provider
+const (
+	policyFIPS1403 = "fips-140-3"
+	policyNIST2030 = "nist-2030"
+	policyCustom   = "custom"
+)
+
+var validPolicies = []string{policyFIPS1403, policyNIST2030, policyCustom}
+
+// Environment variable with the policy, used when 'policy' is not given in the provider.
+// Functions have no access to the provider configuration, so they only use this one.
+const policyEnvVar = "JWK_POLICY"
+
+// Rules of the policies, named in the diagnostics of violations
+const (
+	ruleDeniedAlgorithm = "denied-algorithm"
+	ruleDeniedCurve     = "denied-curve"
+	ruleMinRSASize      = "min-rsa-size"
+	ruleMinHMACSize     = "min-hmac-size"
+)
+
+// Recommendations checked for all keys, reported as warnings
+const (
+	ruleAlgorithmMissing     = "algorithm-missing"
+	ruleRSASizeForAlgorithm  = "rsa-size-for-algorithm"
+	ruleMinSymmetricKeySize  = "min-symmetric-size"
+	recommendedSymmetricSize = 256
+)
+
+// Cryptographic policy, restricting algorithms, curves and key sizes of all keys of the
+// provider. A nil *keyPolicy only checks the recommendations.
+type keyPolicy struct {
+	name             string
+	deniedAlgorithms map[string]string // Reason per algorithm
+	deniedCurves     map[string]string // Reason per curve
+	minRSASize       int64
+	minHMACSize      int64
+}
+
+// Rules of FIPS 140-3 approved algorithms (FIPS 186-5, SP 800-56A/B, SP 800-131A)
+var fipsDeniedAlgorithms = map[string]string{
+	"RSA1_5": "RSAES-PKCS1-v1_5 key transport is not approved (SP 800-131A)",
+	"none":   "unsecured JWS have no integrity protection",
+	"ES256K": "secp256k1 is not an approved curve (SP 800-186)",
+}
+
+var fipsDeniedCurves = map[string]string{
+	"secp256k1": "not an approved curve (SP 800-186)",
+	"X25519":    "not approved for key agreement (SP 800-56A)",
+	"X448":      "not approved for key agreement (SP 800-56A)",
+}
+
+// Named policies. 'nist-2030' requires 128 bits of security, as 112 bits are disallowed
+// after 2030 (SP 800-57, SP 800-131A).
+var namedKeyPolicies = map[string]keyPolicy{
+	policyFIPS1403: {
+		name:             policyFIPS1403,
+		deniedAlgorithms: fipsDeniedAlgorithms,
+		deniedCurves:     fipsDeniedCurves,
+		minRSASize:       2048,
+		minHMACSize:      256,
+	},
+	policyNIST2030: {
+		name:             policyNIST2030,
+		deniedAlgorithms: fipsDeniedAlgorithms,
+		deniedCurves:     fipsDeniedCurves,
+		minRSASize:       3072,
+		minHMACSize:      256,
+	},
+}
+
+// Get a named policy, 'custom' is configured by 'custom_policy' of the provider
+func newKeyPolicy(name string) (*keyPolicy, error) {
+	policy, ok := namedKeyPolicies[name]
+	if !ok {
+		return nil, fmt.Errorf("expected one of '%s', got '%s'", strings.Join(validPolicies, "', '"), name)
+	}
+	return &policy, nil
+}
+
+// Create the policy of the provider from 'policy' and 'custom_policy', or from the
+// JWK_POLICY environment variable, reporting invalid values on their attributes
+func configureKeyPolicy(ctx context.Context, name types.String, custom *customPolicyModel) (*keyPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if name.IsUnknown() {
+		diags.AddAttributeError(path.Root("policy"), "Unknown policy", "The 'policy' must be known during plan.")
+		return nil, diags
+	}
+
+	policyName := name.ValueString()
+	if name.IsNull() {
+		policyName = os.Getenv(policyEnvVar)
+	}
+
+	switch {
+	case policyName == policyCustom:
+		if custom == nil {
+			diags.AddAttributeError(path.Root("custom_policy"), "Missing 'custom_policy'",
+				fmt.Sprintf("Policy '%s' requires the rules in 'custom_policy'.", policyCustom))
+			return nil, diags
+		}
+		return newCustomKeyPolicy(ctx, custom)
+
+	case custom != nil:
+		diags.AddAttributeError(path.Root("custom_policy"), "Unexpected 'custom_policy'",
+			fmt.Sprintf("'custom_policy' is only used with policy '%s'.", policyCustom))
+		return nil, diags
+
+	case policyName == "":
+		return nil, diags
+	}
+
+	policy, err := newKeyPolicy(policyName)
+	if err != nil {
+		diags.AddAttributeError(path.Root("policy"), "Invalid attribute value for 'policy'", err.Error())
+		return nil, diags
+	}
+	return policy, diags
+}
+
+// Create the 'custom' policy from the rules of 'custom_policy'
+func newCustomKeyPolicy(ctx context.Context, model *customPolicyModel) (*keyPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	root := path.Root("custom_policy")
+
+	policy := &keyPolicy{
+		name:             policyCustom,
+		deniedAlgorithms: map[string]string{},
+		deniedCurves:     map[string]string{},
+		minRSASize:       model.MinRSASize.ValueInt64(),
+		minHMACSize:      model.MinHMACSize.ValueInt64(),
+	}
+
+	var algorithms, curves []string
+	diags.Append(model.DeniedAlgorithms.ElementsAs(ctx, &algorithms, false)...)
+	diags.Append(model.DeniedCurves.ElementsAs(ctx, &curves, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	validAlgorithms := knownAlgorithms()
+	for i, alg := range algorithms {
+		if !isValid(alg, validAlgorithms) {
+			diags.AddAttributeError(root.AtName("denied_algorithms").AtListIndex(i), "Invalid algorithm in 'denied_algorithms'",
+				fmt.Sprintf("Expected one of %v, got '%s'", validAlgorithms, alg))
+			continue
+		}
+		policy.deniedAlgorithms[alg] = "denied by the custom policy"
+	}
+
+	validCurves := knownCurves()
+	for i, crv := range curves {
+		if !isValid(crv, validCurves) {
+			diags.AddAttributeError(root.AtName("denied_curves").AtListIndex(i), "Invalid curve in 'denied_curves'",
+				fmt.Sprintf("Expected one of %v, got '%s'", validCurves, crv))
+			continue
+		}
+		policy.deniedCurves[crv] = "denied by the custom policy"
+	}
+
+	if policy.minRSASize < 0 {
+		diags.AddAttributeError(root.AtName("min_rsa_size"), "Invalid attribute value for 'min_rsa_size'",
+			fmt.Sprintf("Expected zero or a positive number, got %d", policy.minRSASize))
+	}
+	if policy.minHMACSize < 0 {
+		diags.AddAttributeError(root.AtName("min_hmac_size"), "Invalid attribute value for 'min_hmac_size'",
+			fmt.Sprintf("Expected zero or a positive number, got %d", policy.minHMACSize))
+	}
+
+	if diags.HasError() {
+		return nil, diags
+	}
+	return policy, diags
+}
+
+// Policy of the functions, taken from the JWK_POLICY environment variable
+func functionPolicy() (*keyPolicy, error) {
+	name := os.Getenv(policyEnvVar)
+	switch name {
+	case "":
+		return nil, nil
+	case policyCustom:
+		return nil, fmt.Errorf("policy '%s' of %s is not supported by functions, use one of '%s' or '%s'",
+			policyCustom, policyEnvVar, policyFIPS1403, policyNIST2030)
+	}
+
+	policy, err := newKeyPolicy(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", policyEnvVar, err)
+	}
+	return policy, nil
+}
+
+// All algorithms known to the provider, e.g. to be denied by a custom policy or to filter a key set
+func knownAlgorithms() []string {
+	algorithms := map[string]bool{"ES256K": true}
+	for _, uses := range keyTypeAlgorithms {
+		for _, algs := range uses {
+			for alg := range algs {
+				algorithms[alg] = true
+			}
+		}
+	}
+	return keys(algorithms)
+}
+
+// All curves known to the provider, e.g. to be denied by a custom policy
+func knownCurves() []string {
+	curves := append([]string{}, validECCurves...)
+	if !isValid("secp256k1", curves) {
+		curves = append(curves, "secp256k1")
+	}
+	curves = append(curves, validOKPSigningCurves...)
+	curves = append(curves, validOKPEncryptionCurves...)
+	sort.Strings(curves)
+	return curves
+}
+
+// Properties of a key checked by a policy. The size is given for RSA and oct keys.
+type policyKey struct {
+	kty  string
+	use  string
+	alg  string
+	crv  string
+	size int64
+}
+
+// Properties of a JWK, e.g. given to a function
+func policyKeyOf(key jwk.Key) policyKey {
+	checked := policyKey{
+		kty: key.KeyType().String(),
+		use: key.KeyUsage(),
+		alg: key.Algorithm().String(),
+		crv: keyCurve(key),
+	}
+
+	switch key.KeyType() {
+	case jwa.RSA:
+		checked.size = int64(rsaKeyBits(key))
+	case jwa.OctetSeq:
+		var raw []byte
+		if err := key.Raw(&raw); err == nil {
+			checked.size = int64(len(raw) * 8)
+		}
+	}
+
+	return checked
+}
+
+// Whether a symmetric key is used for HMAC, which is assumed for keys without algorithm, unless used for encryption
+func (k policyKey) isHMAC() bool {
+	return k.kty == "oct" && (strings.HasPrefix(k.alg, "HS") || (k.alg == "" && k.use != "enc"))
+}
+
+// A rule not followed by a key, with the attribute it applies to
+type policyViolation struct {
+	rule      string
+	attribute string
+	detail    string
+}
+
+// Rules of the policy violated by the key
+func (p *keyPolicy) violations(key policyKey) []policyViolation {
+	if p == nil {
+		return nil
+	}
+
+	var violations []policyViolation
+
+	if reason, ok := p.deniedAlgorithms[key.alg]; ok {
+		violations = append(violations, policyViolation{ruleDeniedAlgorithm, "alg",
+			fmt.Sprintf("Algorithm '%s' is not allowed: %s.", key.alg, reason)})
+	}
+	if reason, ok := p.deniedCurves[key.crv]; ok {
+		violations = append(violations, policyViolation{ruleDeniedCurve, "crv",
+			fmt.Sprintf("Curve '%s' is not allowed: %s.", key.crv, reason)})
+	}
+	if key.kty == "RSA" && key.size < p.minRSASize {
+		violations = append(violations, policyViolation{ruleMinRSASize, "size",
+			fmt.Sprintf("RSA keys must be at least %d bits, got %d bits.", p.minRSASize, key.size)})
+	}
+	if key.isHMAC() && key.alg != "none" && key.size < p.minHMACSize {
+		violations = append(violations, policyViolation{ruleMinHMACSize, "size",
+			fmt.Sprintf("HMAC keys must be at least %d bits, got %d bits.", p.minHMACSize, key.size)})
+	}
+
+	return violations
+}
+
+// Recommendations not followed by the key, independent of the policy
+func recommendationViolations(key policyKey) []policyViolation {
+	var violations []policyViolation
+
+	if key.alg == "" && key.use != "" {
+		violations = append(violations, policyViolation{ruleAlgorithmMissing, "alg",
+			fmt.Sprintf("No 'alg' attribute for '%s' use. Consider setting one of '%s'.",
+				key.use, keys(keyTypeAlgorithms[key.kty][key.use]))})
+	}
+
+	if expectedSize, ok := RSASignatureAlgorithms[key.alg]; ok && key.kty == "RSA" && key.size < int64(expectedSize) {
+		violations = append(violations, policyViolation{ruleRSASizeForAlgorithm, "size",
+			fmt.Sprintf("Algorithm '%s' should use at least %d bits. Current size: %d bits.", key.alg, expectedSize, key.size)})
+	}
+
+	// Algorithms for smaller keys, e.g. A128KW, are fine with the size they require
+	if key.kty == "oct" && key.alg != "none" && key.alg != "dir" && key.size < recommendedSymmetricSize {
+		requiredSize, ok := keyTypeAlgorithms["oct"][key.use][key.alg]
+		if !ok || int64(requiredSize) > key.size {
+			violations = append(violations, policyViolation{ruleMinSymmetricKeySize, "size",
+				fmt.Sprintf("General security recommendation is at least %d bits, got '%d' bits.", recommendedSymmetricSize, key.size)})
+		}
+	}
+
+	return violations
+}
+
+// Check a key of a resource against the policy and the recommendations. Violations of the
+// policy are errors, recommendations are warnings, both reported on the attribute of the rule.
+func (p *keyPolicy) checkKey(key policyKey) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, violation := range p.violations(key) {
+		p.addViolationError(&diags, path.Root(violation.attribute), violation)
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	for _, violation := range recommendationViolations(key) {
+		diags.AddAttributeWarning(path.Root(violation.attribute),
+			fmt.Sprintf("Recommendation '%s' not followed", violation.rule), violation.detail)
+	}
+	return diags
+}
+
+// Check a key against the policy, reporting violations on the given path, e.g. of a key in a key set
+func (p *keyPolicy) checkKeyAt(key policyKey, at path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, violation := range p.violations(key) {
+		p.addViolationError(&diags, at, violation)
+	}
+	return diags
+}
+
+// Report a violation of the policy, naming the rule
+func (p *keyPolicy) addViolationError(diags *diag.Diagnostics, at path.Path, violation policyViolation) {
+	diags.AddAttributeError(at,
+		fmt.Sprintf("Policy rule '%s' violated", violation.rule),
+		fmt.Sprintf("Policy '%s': %s", p.name, violation.detail))
+}
+
+// Check a key used by a function. The algorithm overrides 'alg' of the key, when given.
+func (p *keyPolicy) checkJWK(key jwk.Key, alg string) error {
+	checked := policyKeyOf(key)
+	if alg != "" {
+		checked.alg = alg
+	}
+
+	if violations := p.violations(checked); len(violations) > 0 {
+		return fmt.Errorf("rule '%s' of policy '%s' violated: %s", violations[0].rule, p.name, violations[0].detail)
+	}
+	return nil
+}
@@ -2,12 +2,19 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // --------------------------------------------------------------------------
@@ -23,8 +30,87 @@ func NewProvider() provider.Provider {
 
 type jwkProvider struct{}
 
+// Provider configuration
+type jwkProviderModel struct {
+	StateEncryption *stateEncryptionModel `tfsdk:"state_encryption"`
+	Defaults        *keyDefaultsModel     `tfsdk:"defaults"`
+	Policy          types.String          `tfsdk:"policy"`
+	CustomPolicy    *customPolicyModel    `tfsdk:"custom_policy"`
+}
+
+type stateEncryptionModel struct {
+	Passphrase types.String `tfsdk:"passphrase"`
+	Key        types.String `tfsdk:"key"`
+}
+
+type keyDefaultsModel struct {
+	Use         types.String `tfsdk:"use"`
+	Algorithms  types.Map    `tfsdk:"algorithms"`
+	RSASize     types.Int64  `tfsdk:"rsa_size"`
+	ECCurve     types.String `tfsdk:"ec_curve"`
+	KIDPrefix   types.String `tfsdk:"kid_prefix"`
+	KIDTemplate types.String `tfsdk:"kid_template"`
+}
+
+type customPolicyModel struct {
+	DeniedAlgorithms types.List  `tfsdk:"denied_algorithms"`
+	DeniedCurves     types.List  `tfsdk:"denied_curves"`
+	MinRSASize       types.Int64 `tfsdk:"min_rsa_size"`
+	MinHMACSize      types.Int64 `tfsdk:"min_hmac_size"`
+}
+
+// Data of the provider configuration, passed to resources in Configure
+type jwkProviderData struct {
+	stateEncryption *stateEncryption
+	defaults        *keyDefaults
+	policy          *keyPolicy
+}
+
+// Gets the state encryption, nil when not configured
+func (d *jwkProviderData) encryption() *stateEncryption {
+	if d == nil {
+		return nil
+	}
+	return d.stateEncryption
+}
+
+// Gets the defaults of the key resources, nil when not configured
+func (d *jwkProviderData) keyDefaults() *keyDefaults {
+	if d == nil {
+		return nil
+	}
+	return d.defaults
+}
+
+// Gets the cryptographic policy, nil when not configured
+func (d *jwkProviderData) keyPolicy() *keyPolicy {
+	if d == nil {
+		return nil
+	}
+	return d.policy
+}
+
+// Gets the provider data in Configure of a resource. Returns nil, when the provider
+// is not configured yet, e.g. during validation.
+func resourceProviderData(providerData any, diags *diag.Diagnostics) *jwkProviderData {
+	if providerData == nil {
+		return nil
+	}
+
+	data, ok := providerData.(*jwkProviderData)
+	if !ok {
+		diags.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *jwkProviderData, got: %T. Please report this issue to the provider developers.", providerData),
+		)
+		return nil
+	}
+
+	return data
+}
+
 func (p *jwkProvider) Documentation() string {
-	return `This provider manages JSON Web Keys (JWKs) for use with EC, RSA and symmetric keys for encryption and signing.
+	return `This provider manages JSON Web Keys (JWKs) for use with EC, OKP, RSA and symmetric keys for encryption and signing.
 Keys are represented in JSON format and include various fields, such as 'kid' (key ID), 'alg' (algorithm), 
 and 'use' (key usage). 
 
@@ -37,20 +123,45 @@ and key format correctness.
 ## Supported Resources:
 - **jwk_rsa_key**: Manages RSA keys.
 - **jwk_ec_key**: Manages Elliptic Curve keys.
+- **jwk_okp_key**: Manages Octet Key Pair keys (Ed25519, Ed448, X25519, X448).
 - **jwk_oct_key**: Manages symmetric keys.
 - **jwk_keyset**: Represents a set of JWK keys, conforming to the JWKS format.
+- **jwk_rotating_key**: Rotates a key periodically, keeping the previous keys for verification.
+
+## Ephemeral Resources:
+- **jwk_rsa_key**, **jwk_ec_key**, **jwk_oct_key**: Generate keys on every run without storing them in state (Terraform 1.10+).
+
+## Data Sources:
+- **jwk_remote_keyset**: Fetches a JWK key set from a remote URL (jwks_uri).
+- **jwk_oidc_discovery**: Retrieves OpenID Provider metadata and signing keys of an issuer.
+- **jwk_key_info**: Parses a JWK and exposes its properties, such as key type, size and thumbprint.
 
 ## Functions
 - **public_key(private_key_json, kid)**: Gets a public key from private key
+- **thumbprint(jwk, hash)**: Computes RFC 7638 JWK Thumbprint, or RFC 9278 JWK Thumbprint URI
+- **parse_jwk(jwk)**: Parses a JWK, returning its properties, such as key type, size and thumbprint
+- **pem_to_jwk(pem, kid, use, alg)**: Converts a PEM encoded key or certificate to JWK
+- **jwk_to_pem(jwk, format)**: Converts a JWK to PEM (PKCS#1, SEC 1, PKCS#8 or SPKI)
+- **sign_jwt(private_jwk, claims, headers)**: Signs claims as a JWT (compact JWS)
+- **verify_jws(jwks_json, token)**: Verifies a JWS with a key set, returning its header and payload
+- **decode_jwt(token)**: Decodes a JWT without verification, returning its header and claims
+- **encrypt_jwe(public_jwk, plaintext, enc, serialization)**: Encrypts plaintext to a key as a JWE
+- **decrypt_jwe(private_jwk, jwe)**: Decrypts a JWE with a private key
+- **decrypt_state(value, secret)**: Decrypts a key encrypted in state by 'state_encryption'
 
 ## Relevant Specifications:
+- [RFC 7515 - JSON Web Signature (JWS)](https://datatracker.ietf.org/doc/html/rfc7515)
+- [RFC 7516 - JSON Web Encryption (JWE)](https://datatracker.ietf.org/doc/html/rfc7516)
 - [RFC 7517 - JSON Web Key (JWK)](https://datatracker.ietf.org/doc/html/rfc7517)
 - [RFC 7518 - JSON Web Algorithms (JWA)](https://datatracker.ietf.org/doc/html/rfc7518)
 - [RFC 7519 - JSON Web Token (JWT)](https://datatracker.ietf.org/doc/html/rfc7519) (for broader JWK usage)
+- [RFC 7638 - JSON Web Key (JWK) Thumbprint](https://datatracker.ietf.org/doc/html/rfc7638)
+- [RFC 9278 - JWK Thumbprint URI](https://datatracker.ietf.org/doc/html/rfc9278)
 
 ## Cryptographic Libraries Used:
 This provider utilizes Go's standard cryptographic libraries for key generation and manipulation:
 - "crypto/ecdsa"
+- "crypto/ed25519"
 - "crypto/elliptic"
 - "crypto/rand"
 - "crypto/rsa"
@@ -69,11 +180,155 @@ func (p *jwkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, re
 func (p *jwkProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: p.Documentation(),
+
+		Attributes: map[string]schema.Attribute{
+			"policy": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf("Cryptographic policy restricting algorithms, curves and key sizes of all keys, one of `%s`. ", strings.Join(validPolicies, "`, `")) +
+					fmt.Sprintf("Keys violating the policy fail the plan, naming the violated rule. Defaults to the `%s` environment variable, ", policyEnvVar) +
+					"which is also the policy of the functions, as functions have no access to the provider configuration. " +
+					"A `policy` differing from `JWK_POLICY` does not protect the functions, and is reported with a warning.",
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"state_encryption": schema.SingleNestedBlock{
+				Description: "Encrypts private keys in state. When given, `json` of the key resources and of `jwk_keyset`, and the keys of `jwk_rotating_key` are stored " +
+					"as a JWE (compact serialization) and private key PEMs are left out of state. Use the `decrypt_state` function " +
+					"to get the plaintext key at apply time. Give either `passphrase` or `key`.",
+				Attributes: map[string]schema.Attribute{
+					"passphrase": schema.StringAttribute{
+						Optional:  true,
+						Sensitive: true,
+						Description: fmt.Sprintf("Passphrase used to encrypt the keys with PBES2-HS512+A256KW. At least %d characters.",
+							minStatePassphraseLength),
+					},
+					"key": schema.StringAttribute{
+						Optional:    true,
+						Sensitive:   true,
+						Description: "Key encryption key (KEK) in JWK format, a 256 bit symmetric (`oct`) key, used to encrypt the keys with A256KW.",
+					},
+				},
+			},
+			"custom_policy": schema.SingleNestedBlock{
+				Description: "Rules of the `custom` policy. Requires `policy` to be `custom`.",
+				Attributes: map[string]schema.Attribute{
+					"denied_algorithms": schema.ListAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "Algorithms not allowed for keys, e.g. `[\"RSA1_5\", \"none\"]`.",
+					},
+					"denied_curves": schema.ListAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "Curves not allowed for EC and OKP keys, e.g. `[\"secp256k1\"]`.",
+					},
+					"min_rsa_size": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Minimum size of RSA keys in bits.",
+					},
+					"min_hmac_size": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Minimum size of HMAC keys in bits, i.e. symmetric keys for signing.",
+					},
+				},
+			},
+			"defaults": schema.SingleNestedBlock{
+				Description: "Defaults of `jwk_rsa_key`, `jwk_ec_key`, `jwk_okp_key` and `jwk_oct_key`, applied when the attributes " +
+					"are not given in the resource. Changing a default updates the keys using it, or replaces them for `rsa_size` and `ec_curve`.",
+				Attributes: map[string]schema.Attribute{
+					"use": schema.StringAttribute{
+						Optional:    true,
+						Description: "Default intended use of the keys, `sig` or `enc`.",
+					},
+					"algorithms": schema.MapAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: fmt.Sprintf("Default algorithm per key type, `%s`, e.g. `{ RSA = \"PS256\", EC = \"ES256\" }`. ", strings.Join(validKeyTypes, "`, `")) +
+							"An algorithm applies to keys with the matching `use` only.",
+					},
+					"rsa_size": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Default size of RSA keys in bits.",
+					},
+					"ec_curve": schema.StringAttribute{
+						Optional:    true,
+						Description: "Default curve of EC keys. The curve required by `alg` takes precedence for signing keys.",
+					},
+					"kid_prefix": schema.StringAttribute{
+						Optional:    true,
+						Description: "Value of `${prefix}` in `kid_template`.",
+					},
+					"kid_template": schema.StringAttribute{
+						Optional: true,
+						Description: "Template of the Key ID, used when neither `kid` nor `kid_strategy` is given, e.g. `$${prefix}-$${kty}-$${date}`. " +
+							fmt.Sprintf("Variables: `${%s}`. ", strings.Join(validKIDTemplateVariables, "}`, `${")) +
+							"Escape them as `$${...}` in Terraform configuration. `${date}` is the UTC date as `YYYYMMDD`, `${timestamp}` the Unix time.",
+					},
+				},
+			},
+		},
 	}
 }
 
 // Configure
 func (p *jwkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var model jwkProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data := &jwkProviderData{}
+
+	if model.StateEncryption != nil {
+		if model.StateEncryption.Passphrase.IsUnknown() || model.StateEncryption.Key.IsUnknown() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("state_encryption"),
+				"Unknown state encryption",
+				"The passphrase or key of 'state_encryption' must be known during plan.",
+			)
+			return
+		}
+
+		encryption, err := newStateEncryption(model.StateEncryption.Passphrase.ValueString(), model.StateEncryption.Key.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("state_encryption"), "Invalid state encryption", err.Error())
+			return
+		}
+		data.stateEncryption = encryption
+	}
+
+	if model.Defaults != nil {
+		defaults, diags := newKeyDefaults(ctx, model.Defaults)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.defaults = defaults
+	}
+
+	policy, diags := configureKeyPolicy(ctx, model.Policy, model.CustomPolicy)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.policy = policy
+
+	// Functions have no access to the provider configuration, they apply the policy of JWK_POLICY only
+	if name := model.Policy.ValueString(); !model.Policy.IsNull() && (name != os.Getenv(policyEnvVar) || name == policyCustom) {
+		detail := fmt.Sprintf("Functions have no access to the provider configuration, so sign_jwt, verify_jws, encrypt_jwe, "+
+			"decrypt_jwe and pem_to_jwk are not protected by policy '%s'. They apply the policy of the %s environment variable only.",
+			name, policyEnvVar)
+		if name != policyCustom {
+			detail += fmt.Sprintf(" Set %s=%s to apply the policy to the functions as well.", policyEnvVar, name)
+		}
+		resp.Diagnostics.AddAttributeWarning(path.Root("policy"), "Policy does not apply to functions", detail)
+	}
+
+	resp.ResourceData = data
+	resp.EphemeralResourceData = data
 }
 
 // Resources
@@ -81,19 +336,44 @@ func (p *jwkProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewJwkKeysetResource,
 		NewJwkECKeyResource,
+		NewJwkOKPKeyResource,
 		NewJwkOctKeyResource,
 		NewJwkRSAKeyResource,
+		NewJwkRotatingKeyResource,
+	}
+}
+
+// EphemeralResources
+func (p *jwkProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewJwkECKeyEphemeralResource,
+		NewJwkOctKeyEphemeralResource,
+		NewJwkRSAKeyEphemeralResource,
 	}
 }
 
 // DataSources
 func (p *jwkProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewJwkRemoteKeysetDataSource,
+		NewJwkOIDCDiscoveryDataSource,
+		NewJwkKeyInfoDataSource,
+	}
 }
 
 // Functions
 func (p *jwkProvider) Functions(_ context.Context) []func() function.Function {
 	return []func() function.Function{
 		NewPublicKeyFunction,
+		NewThumbprintFunction,
+		NewParseJWKFunction,
+		NewPemToJWKFunction,
+		NewJwkToPEMFunction,
+		NewSignJWTFunction,
+		NewVerifyJWSFunction,
+		NewDecodeJWTFunction,
+		NewEncryptJWEFunction,
+		NewDecryptJWEFunction,
+		NewDecryptStateFunction,
 	}
 }
@@ -6,9 +6,15 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwa"
 )
 
 // Elliptic curve (EC) constants
@@ -43,21 +49,36 @@ var validECCurves = []string{ // Elliptic curves
 	"P-256", "P-384", "P-521",
 }
 
+// Curves allowed on encryption (ECDH-ES is defined for NIST curves only)
+var validECEncryptionCurves = []string{
+	"P-256", "P-384", "P-521",
+}
+
 // Creates a new instance of the jwkECKeyResource.
 func NewJwkECKeyResource() resource.Resource {
 	return &jwkECKeyResource{}
 }
 
 // jwkECKeyResource is a custom resource that generates a JSON Web Key (JWK) in EC format.
-type jwkECKeyResource struct{}
+type jwkECKeyResource struct {
+	providerData *jwkProviderData
+}
 
 // This struct gets populated with the configuration values
 type jwkECKeyModel struct {
-	KID     types.String `tfsdk:"kid"`
-	Use     types.String `tfsdk:"use"`
-	Crv     types.String `tfsdk:"crv"`
-	Alg     types.String `tfsdk:"alg"`
-	KeyJSON types.String `tfsdk:"json"`
+	KID                types.String         `tfsdk:"kid"`
+	KIDStrategy        types.String         `tfsdk:"kid_strategy"`
+	Use                types.String         `tfsdk:"use"`
+	Crv                types.String         `tfsdk:"crv"`
+	Alg                types.String         `tfsdk:"alg"`
+	Keepers            types.Map            `tfsdk:"keepers"`
+	KeyJSON            types.String         `tfsdk:"json"`
+	Thumbprint         types.String         `tfsdk:"thumbprint"`
+	PrivateKeyPEM      types.String         `tfsdk:"private_key_pem"`
+	PrivateKeyPEMPKCS8 types.String         `tfsdk:"private_key_pem_pkcs8"`
+	PublicKeyPEM       types.String         `tfsdk:"public_key_pem"`
+	CertificatePEM     types.String         `tfsdk:"certificate_pem"`
+	Certificate        *jwkCertificateModel `tfsdk:"certificate"`
 }
 
 // Resource Documentation
@@ -74,6 +95,11 @@ func (r *jwkECKeyResource) Metadata(_ context.Context, _ resource.MetadataReques
 	resp.TypeName = "jwk_ec_key"
 }
 
+// Resource Configure
+func (r *jwkECKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.providerData = resourceProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
 // Resource Schema
 func (r *jwkECKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	sigAlgs := keys(ECSigAlgorithms)
@@ -84,30 +110,89 @@ func (r *jwkECKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 
 		Attributes: map[string]schema.Attribute{
 			"kid": schema.StringAttribute{
-				Required:    true,
-				Description: "The Key ID (KID) is a unique identifier for the key. It is used to distinguish different keys in a key set.",
+				Optional: true,
+				Computed: true,
+				Description: "The Key ID (KID) is a unique identifier for the key. It is used to distinguish different keys in a key set. " +
+					"If not given, the Key ID is generated according to `kid_strategy`.",
+				PlanModifiers: []planmodifier.String{kidPlanModifier{}},
+			},
+			"kid_strategy": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"Strategy used to generate the Key ID, when `kid` is not given. `%s`. Defaults to `%s`, the RFC 7638 "+
+						"JWK Thumbprint (base64url encoded SHA-256), which can be verified by anyone holding the key.",
+					strings.Join(validKIDStrategies, "`, `"), defaultKIDStrategy,
+				),
+				Validators: []validator.String{stringOneOf(validKIDStrategies...)},
 			},
 			"use": schema.StringAttribute{
-				Required:    true,
-				Description: "Specifies the intended use of the key. Allowed values: `sig` (for signing) and `enc` (for encryption).",
+				Optional: true,
+				Computed: true,
+				Description: "Specifies the intended use of the key. Allowed values: `sig` (for signing) and `enc` (for encryption). " +
+					"Required, unless `use` is given in `defaults` of the provider.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+				Validators:    []validator.String{stringOneOf(validUses...)},
 			},
 			"crv": schema.StringAttribute{
-				Required:    true,
-				Description: "Elliptic curve used for the key. Common values include `P-256`, `P-384`, and `P-521`.",
+				Optional: true,
+				Computed: true,
+				Description: "Elliptic curve used for the key. Common values include `P-256`, `P-384`, and `P-521`. `secp256k1` is available for `ES256K` signing keys. " +
+					"Changing it replaces the key. Defaults to the curve required by `alg` for signing keys, otherwise to `ec_curve` in `defaults` of the provider.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown(), stringplanmodifier.RequiresReplace()},
+				Validators:    []validator.String{stringOneOf(validECCurves...)},
 			},
 			"alg": schema.StringAttribute{
 				Optional: true,
+				Computed: true,
 				Description: fmt.Sprintf(
-					"The cryptographic algorithm associated with the key. `%s` for signing, `%s` for encryption",
+					"The cryptographic algorithm associated with the key. `%s` for signing, `%s` for encryption. "+
+						"Defaults to the algorithm for `EC` in `defaults` of the provider, when it suits `use`.",
 					strings.Join(sigAlgs, "`, `"), strings.Join(encAlgs, "`, `"),
 				),
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"keepers": keepersAttribute(),
+			"thumbprint": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The RFC 7638 JWK Thumbprint of the key (base64url encoded SHA-256). It is not sensitive, so changes of the key are visible in plans and outputs.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"private_key_pem": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+				Description: "The private key in PEM format, SEC 1 (`EC PRIVATE KEY`). Empty for `secp256k1` keys, for which PEM encoding is not supported. " +
+					"Null, when `state_encryption` is configured in the provider.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"private_key_pem_pkcs8": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+				Description: "The private key in PEM format, PKCS#8 (`PRIVATE KEY`). Empty for `secp256k1` keys, for which PEM encoding is not supported. " +
+					"Null, when `state_encryption` is configured in the provider.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"public_key_pem": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The public key in PEM format, PKIX SubjectPublicKeyInfo (`PUBLIC KEY`). Empty for `secp256k1` keys, for which PEM encoding is not supported.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"certificate_pem": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The self-signed certificate in PEM format, when the `certificate` block is given.",
+				PlanModifiers: []planmodifier.String{useStateWhenUnchanged("kid", "use", "alg", "certificate")},
 			},
 			"json": schema.StringAttribute{
-				Computed:    true,
-				Sensitive:   true,
-				Description: "The JSON representation of the key in JWK (JSON Web Key) format. This value is automatically generated.",
+				Computed:  true,
+				Sensitive: true,
+				Description: "The JSON representation of the key in JWK (JSON Web Key) format. This value is automatically generated. " +
+					"Encrypted as a JWE, when `state_encryption` is configured in the provider, see the `decrypt_state` function.",
+				PlanModifiers: []planmodifier.String{useStateWhenUnchanged("kid", "use", "alg", "certificate")},
 			},
 		},
+
+		Blocks: map[string]schema.Block{
+			"certificate": certificateBlock(),
+		},
 	}
 }
 
@@ -131,41 +216,135 @@ func (r *jwkECKeyResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
+	if err := r.providerData.keyDefaults().setComputedKID(key, model.KID, model.KIDStrategy); err != nil {
+		resp.Diagnostics.AddError("Key ID Generation Failed", err.Error())
+		return
+	}
+	model.KID = types.StringValue(key.KeyID())
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
+		return
+	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	model.CertificatePEM = types.StringNull()
+	if model.Certificate != nil {
+		certPEM, err := addSelfSignedCertificate(ctx, key, model.Certificate)
+		if err != nil {
+			resp.Diagnostics.AddError("Certificate Generation Failed", err.Error())
+			return
+		}
+		model.CertificatePEM = types.StringValue(certPEM)
+	}
+
 	keyJSON, err := json.Marshal(key)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create EC key", err.Error())
 		return
 	}
 
-	model.KeyJSON = types.StringValue(string(keyJSON))
+	sealedJSON, err := r.providerData.encryption().seal(string(keyJSON))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt EC key for state", err.Error())
+		return
+	}
+	model.KeyJSON = types.StringValue(sealedJSON)
+
+	privatePEM, pkcs8PEM, publicPEM, err := keyPEMs(key, pemFormatSEC1)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode EC key as PEM", err.Error())
+		return
+	}
+	model.PrivateKeyPEM = r.providerData.encryption().privateValue(privatePEM)
+	model.PrivateKeyPEMPKCS8 = r.providerData.encryption().privateValue(pkcs8PEM)
+	model.PublicKeyPEM = types.StringValue(publicPEM)
 
 	diags = resp.State.Set(ctx, model)
 	resp.Diagnostics.Append(diags...)
 }
 
-// Update is identical to Create, so we could reuse some code here
+// Update keeps the key material, as changes of it replace the key. Only the metadata is updated.
 func (r *jwkECKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var model jwkECKeyModel
+	var model, state jwkECKeyModel
 
 	diags := req.Plan.Get(ctx, &model)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	key, err := generateECJWK(model.KID.ValueString(), model.Use.ValueString(), model.Alg.ValueString(), model.Crv.ValueString())
+	// The key is kept as it is, when no attribute of it changed, e.g. only 'kid_strategy'
+	if !model.KeyJSON.IsUnknown() {
+		diags = resp.State.Set(ctx, model)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	stateJSON, err := r.providerData.encryption().open(state.KeyJSON.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("EC Key Generation Failed", err.Error())
+		resp.Diagnostics.AddError("Failed to decrypt JWK in state", err.Error())
 		return
 	}
 
+	key, err := json2jwk(stateJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK in state", err.Error())
+		return
+	}
+
+	if err := updateKeyMetadata(key, model.KID, model.Use, model.Alg); err != nil {
+		resp.Diagnostics.AddError("Key Update Failed", err.Error())
+		return
+	}
+
+	if err := r.providerData.keyDefaults().setComputedKID(key, model.KID, model.KIDStrategy); err != nil {
+		resp.Diagnostics.AddError("Key ID Generation Failed", err.Error())
+		return
+	}
+
+	model.KID = types.StringValue(key.KeyID())
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
+		return
+	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	model.CertificatePEM = types.StringNull()
+	if model.Certificate != nil {
+		certPEM, err := addSelfSignedCertificate(ctx, key, model.Certificate)
+		if err != nil {
+			resp.Diagnostics.AddError("Certificate Generation Failed", err.Error())
+			return
+		}
+		model.CertificatePEM = types.StringValue(certPEM)
+	}
+
 	keyJSON, err := json.Marshal(key)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create EC key", err.Error())
 		return
 	}
 
-	model.KeyJSON = types.StringValue(string(keyJSON))
+	sealedJSON, err := r.providerData.encryption().seal(string(keyJSON))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt EC key for state", err.Error())
+		return
+	}
+	model.KeyJSON = types.StringValue(sealedJSON)
+
+	privatePEM, pkcs8PEM, publicPEM, err := keyPEMs(key, pemFormatSEC1)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode EC key as PEM", err.Error())
+		return
+	}
+	model.PrivateKeyPEM = r.providerData.encryption().privateValue(privatePEM)
+	model.PrivateKeyPEMPKCS8 = r.providerData.encryption().privateValue(pkcs8PEM)
+	model.PublicKeyPEM = types.StringValue(publicPEM)
 
 	diags = resp.State.Set(ctx, model)
 	resp.Diagnostics.Append(diags...)
@@ -186,14 +365,19 @@ func (r *jwkECKeyResource) ImportState(ctx context.Context, req resource.ImportS
 		return
 	}
 
-	// Check mandatory fields
+	// Check mandatory fields. Key ID is derived from the key, when not given
+	keyJSON := req.ID
 	kid, ok := jwk["kid"].(string)
 	if !ok {
-		resp.Diagnostics.AddError(
-			"Missing Key ID",
-			"Imported JWK must contain 'kid' field",
-		)
-		return
+		var err error
+		kid, keyJSON, err = thumbprintKID(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Missing Key ID",
+				fmt.Sprintf("Could not derive Key ID of imported JWK: %s", err.Error()),
+			)
+			return
+		}
 	}
 
 	use, ok := jwk["use"].(string)
@@ -250,6 +434,14 @@ func (r *jwkECKeyResource) ImportState(ctx context.Context, req resource.ImportS
 			}
 		}
 	} else if use == "enc" {
+		if !isValid(crv, validECEncryptionCurves) {
+			resp.Diagnostics.AddError(
+				"Invalid Curve",
+				fmt.Sprintf("Unsupported encryption curve '%s'. Valid curves are: %v", crv, validECEncryptionCurves),
+			)
+			return
+		}
+
 		if alg != "" {
 			_, exists := ECEncAlgorithms[alg]
 			if !exists {
@@ -262,12 +454,58 @@ func (r *jwkECKeyResource) ImportState(ctx context.Context, req resource.ImportS
 		}
 	}
 
+	thumbprint, err := jsonThumbprint(keyJSON)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid JWK JSON",
+			fmt.Sprintf("Could not compute thumbprint of imported JWK: %s", err.Error()),
+		)
+		return
+	}
+
+	importedKey, err := json2jwk(keyJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK JSON", err.Error())
+		return
+	}
+
+	privatePEM, pkcs8PEM, publicPEM, err := keyPEMs(importedKey, pemFormatSEC1)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid JWK JSON",
+			fmt.Sprintf("Could not encode imported JWK as PEM: %s", err.Error()),
+		)
+		return
+	}
+
+	certPEM, err := certificatePEM(importedKey)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK JSON", err.Error())
+		return
+	}
+	certificatePEMValue := types.StringNull()
+	if certPEM != "" {
+		certificatePEMValue = types.StringValue(certPEM)
+	}
+
+	sealedJSON, err := r.providerData.encryption().seal(keyJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt imported JWK for state", err.Error())
+		return
+	}
+
 	model := jwkECKeyModel{
-		KID:     types.StringValue(kid),
-		Use:     types.StringValue(use),
-		Crv:     types.StringValue(crv),
-		Alg:     types.StringValue(alg),
-		KeyJSON: types.StringValue(req.ID),
+		KID:                types.StringValue(kid),
+		Use:                types.StringValue(use),
+		Crv:                types.StringValue(crv),
+		Alg:                types.StringValue(alg),
+		Keepers:            types.MapNull(types.StringType),
+		KeyJSON:            types.StringValue(sealedJSON),
+		Thumbprint:         types.StringValue(thumbprint),
+		PrivateKeyPEM:      r.providerData.encryption().privateValue(privatePEM),
+		PrivateKeyPEMPKCS8: r.providerData.encryption().privateValue(pkcs8PEM),
+		PublicKeyPEM:       types.StringValue(publicPEM),
+		CertificatePEM:     certificatePEMValue,
 	}
 
 	// Store model to state
@@ -284,20 +522,164 @@ func (r *jwkECKeyResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	// Validate the JWK JSON from state
-	var key map[string]interface{}
-	if err := json.Unmarshal([]byte(model.KeyJSON.ValueString()), &key); err != nil {
-		resp.Diagnostics.AddError(
-			"Invalid JWK in state",
-			fmt.Sprintf("Could not parse stored JWK: %s", err.Error()),
-		)
+	stored := model.KeyJSON.ValueString()
+	keyJSON, err := r.providerData.encryption().open(stored)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to decrypt JWK in state", err.Error())
+		return
+	}
+
+	// Verify the key is still valid and consistent
+	key, err := parseStateKey(keyJSON, jwa.EC)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK in state", err.Error())
 		return
 	}
 
+	// Attributes follow the key, so that drift shows up in plan
+	model.KID = keyAttributeValue(model.KID, key.KeyID())
+	model.Use = keyAttributeValue(model.Use, key.KeyUsage())
+	model.Alg = keyAttributeValue(model.Alg, key.Algorithm().String())
+	model.Crv = keyAttributeValue(model.Crv, keyCurve(key))
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
+		return
+	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	privatePEM, pkcs8PEM, publicPEM, err := keyPEMs(key, pemFormatSEC1)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode EC key as PEM", err.Error())
+		return
+	}
+	model.PrivateKeyPEM = r.providerData.encryption().privateValue(privatePEM)
+	model.PrivateKeyPEMPKCS8 = r.providerData.encryption().privateValue(pkcs8PEM)
+	model.PublicKeyPEM = types.StringValue(publicPEM)
+
+	certPEM, err := certificatePEM(key)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK in state", err.Error())
+		return
+	}
+	model.CertificatePEM = types.StringNull()
+	if certPEM != "" {
+		model.CertificatePEM = types.StringValue(certPEM)
+	}
+
+	// Encrypt keys stored before state encryption was enabled
+	if r.providerData.encryption().enabled() && !isSealed(stored) {
+		sealedJSON, err := r.providerData.encryption().seal(keyJSON)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to encrypt JWK in state", err.Error())
+			return
+		}
+		model.KeyJSON = types.StringValue(sealedJSON)
+	}
+
 	diags = resp.State.Set(ctx, model)
 	resp.Diagnostics.Append(diags...)
 }
 
+// -----------------------------------------------------------------------------
+// ---    Plan Modification    -------------------------------------------------
+// -----------------------------------------------------------------------------
+
+// ModifyPlan applies the defaults of the provider to the attributes not given in the
+// configuration, and checks the resulting key specification.
+func (r *jwkECKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, plan jwkECKeyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	defaults := r.providerData.keyDefaults()
+	plan.Use = defaultString(config.Use, plan.Use, defaults.useValue())
+	plan.Alg = defaultString(config.Alg, plan.Alg, defaults.algValue("EC", plan.Use))
+
+	// The default algorithm of signing keys applies only, when it suits the given curve
+	if config.Alg.IsNull() && !config.Crv.IsNull() && !plan.Alg.IsNull() && plan.Use.ValueString() == "sig" {
+		if config.Crv.IsUnknown() {
+			plan.Alg = types.StringUnknown()
+		} else if ECSigningAlgorithmsToCurves[plan.Alg.ValueString()] != config.Crv.ValueString() {
+			plan.Alg = types.StringNull()
+		}
+	}
+
+	// Signing algorithms require a curve, otherwise the default curve applies
+	crv := defaults.ecCurveValue()
+	if expectedCrv, ok := ECSigningAlgorithmsToCurves[plan.Alg.ValueString()]; ok && plan.Use.ValueString() == "sig" {
+		crv = types.StringValue(expectedCrv)
+	} else if plan.Use.IsUnknown() || plan.Alg.IsUnknown() {
+		crv = types.StringUnknown()
+	}
+	plan.Crv = defaultString(config.Crv, plan.Crv, crv)
+
+	if plan.Use.IsNull() {
+		addMissingDefaultError(&resp.Diagnostics, "use", "use")
+	}
+	if plan.Crv.IsNull() {
+		addMissingDefaultError(&resp.Diagnostics, "crv", "ec_curve")
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Checks skipped in ValidateConfig, as the values were not known without the defaults
+	if (config.Use.IsNull() || config.Alg.IsNull() || config.Crv.IsNull()) &&
+		!plan.Use.IsUnknown() && !plan.Alg.IsUnknown() && !plan.Crv.IsUnknown() {
+		check := plan
+		check.KID = config.KID
+		resp.Diagnostics.Append(validateECCertificateCurve(check)...)
+		resp.Diagnostics.Append(validateECKeyConfig(check)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Policy of the provider, checked with the defaults applied
+	if !plan.Use.IsUnknown() && !plan.Alg.IsUnknown() && !plan.Crv.IsUnknown() {
+		resp.Diagnostics.Append(r.providerData.keyPolicy().checkKey(policyKey{
+			kty: "EC",
+			use: plan.Use.ValueString(),
+			alg: plan.Alg.ValueString(),
+			crv: plan.Crv.ValueString(),
+		})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state jwkECKeyModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Changed defaults update the key like changes in configuration
+		if !plan.Crv.Equal(state.Crv) {
+			resp.RequiresReplace = resp.RequiresReplace.Append(path.Root("crv"))
+		}
+		if !plan.Use.Equal(state.Use) || !plan.Alg.Equal(state.Alg) {
+			plan.KeyJSON = types.StringUnknown()
+			plan.CertificatePEM = types.StringUnknown()
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
 // -----------------------------------------------------------------------------
 // ---    Validate Configuration    --------------------------------------------
 // -----------------------------------------------------------------------------
@@ -311,51 +693,85 @@ func (r jwkECKeyResource) ValidateConfig(ctx context.Context, req resource.Valid
 		return
 	}
 
+	resp.Diagnostics.Append(validateCertificateConfig(ctx, model.Certificate)...)
+
+	// Attributes not given may be set from the defaults of the provider, and are checked in ModifyPlan
+	if model.Use.IsNull() || model.Alg.IsNull() || model.Crv.IsNull() {
+		resp.Diagnostics.Append(validateKIDConfig(model.KID, model.KIDStrategy)...)
+		return
+	}
+
+	resp.Diagnostics.Append(validateECCertificateCurve(model)...)
+	resp.Diagnostics.Append(validateECKeyConfig(model)...)
+}
+
+// X.509 certificates are supported on NIST curves only
+func validateECCertificateCurve(model jwkECKeyModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if model.Certificate != nil && !model.Crv.IsUnknown() && !isValid(model.Crv.ValueString(), pemECCurves) {
+		diags.AddAttributeError(
+			path.Root("certificate"),
+			"Unsupported 'certificate' block",
+			fmt.Sprintf("Certificates are not supported on curve '%s'", model.Crv.ValueString()),
+		)
+	}
+
+	return diags
+}
+
+// Validate the configuration of an EC key. Shared by the resource and the ephemeral resource.
+func validateECKeyConfig(model jwkECKeyModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	diags.Append(validateKIDConfig(model.KID, model.KIDStrategy)...)
+
+	// 'use' and 'crv' are checked by the validators of the attributes, 'alg' and 'crv' depend on 'use'
+	if model.Use.IsUnknown() || model.Alg.IsUnknown() {
+		return diags
+	}
+
 	crv := model.Crv.ValueString()
 	alg := model.Alg.ValueString()
+	crvValid := !model.Crv.IsUnknown() && isValid(crv, validECCurves)
 
-	if model.Use.ValueString() == "sig" {
+	switch model.Use.ValueString() {
+	case "sig":
 		// Check, alg is allowed on 'sig'
 		expectedCrv, exists := ECSigningAlgorithmsToCurves[alg]
 		if !exists {
-			resp.Diagnostics.AddError(
+			diags.AddAttributeError(
+				path.Root("alg"),
 				"Invalid 'alg' attribute for use: 'sig'",
 				fmt.Sprintf("Expected one of %s, got %s", keys(ECSigAlgorithms), alg),
 			)
-			return
-		}
-
-		// crv needs to match signing algorithm
-		if crv != expectedCrv {
-			resp.Diagnostics.AddError(
+		} else if crvValid && crv != expectedCrv {
+			// crv needs to match signing algorithm
+			diags.AddAttributeError(
+				path.Root("crv"),
 				"Inconsistent 'crv' for given 'alg'",
 				fmt.Sprintf("Algorithm '%s' requires curve '%s', but got '%s'", alg, expectedCrv, crv),
 			)
-			return
 		}
-	} else if model.Use.ValueString() == "enc" {
+	case "enc":
 		// Check, alg is allowed on 'enc'
-		_, exists := ECEncAlgorithms[alg]
-		if !exists {
-			resp.Diagnostics.AddError(
+		if _, exists := ECEncAlgorithms[alg]; !exists {
+			diags.AddAttributeError(
+				path.Root("alg"),
 				"Invalid 'alg' attribute for use: 'enc'",
 				fmt.Sprintf("Expected one of %s, got %s", keys(ECEncAlgorithms), alg),
 			)
-			return
 		}
 
 		// Check crv
-		if !isValid(crv, validECCurves) {
-			resp.Diagnostics.AddError(
+		if crvValid && !isValid(crv, validECEncryptionCurves) {
+			diags.AddAttributeError(
+				path.Root("crv"),
 				"Invalid 'crv' attribute for use: 'enc'",
-				fmt.Sprintf("Expected one of '%s', got '%s'", strings.Join(validECCurves, ", "), crv),
+				fmt.Sprintf("Expected one of '%s', got '%s'", strings.Join(validECEncryptionCurves, ", "), crv),
 			)
-			return
 		}
-	} else {
-		resp.Diagnostics.AddError(
-			"Invalid 'use' attribute",
-			fmt.Sprintf("Expected 'sig' or 'enc', got '%s'", model.Use.ValueString()),
-		)
 	}
+
+	return diags
 }
@@ -2,18 +2,65 @@ package provider
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+// Handling of symmetric keys in public key set
+const (
+	octKeysDrop  = "drop"
+	octKeysError = "error"
+)
+
+var validOctKeyHandlings = []string{octKeysDrop, octKeysError}
+
 type KeysetModel struct {
-	Keys       types.List   `tfsdk:"keys"`
-	KeysetJSON types.String `tfsdk:"json"`
+	Keys             types.List         `tfsdk:"keys"`
+	SourceKeysets    types.List         `tfsdk:"source_keysets"`
+	Filter           *keysetFilterModel `tfsdk:"filter"`
+	Sort             types.String       `tfsdk:"sort"`
+	Deduplicate      types.Bool         `tfsdk:"deduplicate"`
+	PublicOctKeys    types.String       `tfsdk:"public_oct_keys"`
+	KeysetJSON       types.String       `tfsdk:"json"`
+	PublicKeysetJSON types.String       `tfsdk:"public_json"`
 }
 
-type jwkKeysetResource struct{}
+// Gets configured handling of oct keys in public key set, 'drop' by default
+func (m KeysetModel) octKeyHandling() string {
+	if m.PublicOctKeys.IsNull() || m.PublicOctKeys.IsUnknown() {
+		return octKeysDrop
+	}
+	return m.PublicOctKeys.ValueString()
+}
+
+// Whether the attributes selecting the keys are known, which they may not be during plan
+func (m KeysetModel) selectionKnown() bool {
+	if m.Sort.IsUnknown() || m.Deduplicate.IsUnknown() {
+		return false
+	}
+	if m.Filter == nil {
+		return true
+	}
+	if m.Filter.Use.IsUnknown() || m.Filter.Algorithms.IsUnknown() || m.Filter.KeyTypes.IsUnknown() {
+		return false
+	}
+	for _, value := range append(m.Filter.Algorithms.Elements(), m.Filter.KeyTypes.Elements()...) {
+		if value.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+type jwkKeysetResource struct {
+	providerData *jwkProviderData
+}
 
 func NewJwkKeysetResource() resource.Resource {
 	return &jwkKeysetResource{}
@@ -21,7 +68,8 @@ func NewJwkKeysetResource() resource.Resource {
 
 // Resource Documentation
 func (r *jwkKeysetResource) Documentation() string {
-	return `Manages a JWK key set. Key sets are used to represent a set of JSON Web Keys (JWKs) in a single JSON object.`
+	return `Manages a JWK key set. Key sets are used to represent a set of JSON Web Keys (JWKs) in a single JSON object. ` +
+		`The keys may be merged from other key sets, filtered, de-duplicated and sorted.`
 }
 
 // Metadata
@@ -29,6 +77,11 @@ func (r *jwkKeysetResource) Metadata(_ context.Context, _ resource.MetadataReque
 	resp.TypeName = "jwk_keyset"
 }
 
+// Configure
+func (r *jwkKeysetResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.providerData = resourceProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
 // Schema
 func (r *jwkKeysetResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
@@ -36,15 +89,65 @@ func (r *jwkKeysetResource) Schema(_ context.Context, _ resource.SchemaRequest,
 
 		Attributes: map[string]schema.Attribute{
 			"keys": schema.ListAttribute{ // A list of JSON-strings
-				Required:    true,
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "An array of keys. Each element in array is a Json representation of the key, or a key encrypted by `state_encryption`.",
+			},
+			"source_keysets": schema.ListAttribute{ // A list of JWKS documents
+				Optional:    true,
 				ElementType: types.StringType,
-				Description: "An array of keys. Each element in array is a Json representation of the key.",
+				Description: "An array of JWK key sets, whose keys are added after `keys`. Each element is a Json representation of a key set, " +
+					"e.g. `json` of another `jwk_keyset`, or a key set encrypted by `state_encryption`.",
+			},
+			"sort": schema.StringAttribute{
+				Optional: true,
+				Description: "Order of the keys in the key set. `input` (default) keeps the order of `keys` and `source_keysets`, " +
+					"`kid` sorts the keys by Key ID.",
+				Validators: []validator.String{stringOneOf(validKeysetSorts...)},
+			},
+			"deduplicate": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether keys with the same JWK thumbprint (RFC 7638) are included only once, the first one is kept. " +
+					"Note that a private key and its public key have the same thumbprint. Defaults to `false`.",
+			},
+			"public_oct_keys": schema.StringAttribute{
+				Optional: true,
+				Description: "Specifies how symmetric (`oct`) keys, which have no public form, are handled in `public_json`. " +
+					"`drop` (default) leaves them out, `error` fails the operation.",
+				Validators: []validator.String{stringOneOf(validOctKeyHandlings...)},
 			},
 			"json": schema.StringAttribute{ // The resulting Keyset JSON
 				Computed:    true,
-				Description: "A Json representation of the JWK key set",
+				Description: "A Json representation of the JWK key set. Encrypted as a JWE, when `state_encryption` is configured in the provider.",
 				Sensitive:   true,
 			},
+			"public_json": schema.StringAttribute{ // The Keyset JSON with public keys only
+				Computed:    true,
+				Description: "A Json representation of the JWK key set, containing only the public keys. Suitable to be published e.g. as jwks_uri.",
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"filter": schema.SingleNestedBlock{
+				Description: "Includes only the keys matching all given parameters. Keys without a filtered parameter do not match.",
+				Attributes: map[string]schema.Attribute{
+					"use": schema.StringAttribute{
+						Optional:    true,
+						Description: "Intended use of the keys, `sig` or `enc`.",
+						Validators:  []validator.String{stringOneOf(validUses...)},
+					},
+					"alg": schema.ListAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "Algorithms of the keys, e.g. `[\"RS256\", \"ES256\"]`.",
+					},
+					"kty": schema.ListAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "Key types of the keys, e.g. `[\"RSA\", \"EC\"]`.",
+					},
+				},
+			},
 		},
 	}
 }
@@ -59,13 +162,45 @@ func (r *jwkKeysetResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	KeysetJSON, err := createJWKKeyset(model.Keys)
+	selected, diags := r.selectKeys(ctx, model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	keys := keysetKeysList(selected)
+
+	// Key IDs may have been unknown during validation
+	if err := checkDuplicateKIDs(keys); err != nil {
+		resp.Diagnostics.AddError("Duplicate key id", err.Error())
+		return
+	}
+
+	// Keys may have been unknown during plan
+	resp.Diagnostics.Append(r.checkKeyPolicy(selected)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	KeysetJSON, err := createJWKKeyset(keys)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create JWK Keyset", err.Error())
 		return
 	}
 
-	model.KeysetJSON = types.StringValue(KeysetJSON)
+	PublicKeysetJSON, err := createPublicJWKKeyset(keys, model.octKeyHandling())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create public JWK Keyset", err.Error())
+		return
+	}
+
+	sealedJSON, err := r.providerData.encryption().seal(KeysetJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt JWK Keyset for state", err.Error())
+		return
+	}
+
+	model.KeysetJSON = types.StringValue(sealedJSON)
+	model.PublicKeysetJSON = types.StringValue(PublicKeysetJSON)
 
 	diags = resp.State.Set(ctx, model)
 	resp.Diagnostics.Append(diags...)
@@ -73,6 +208,66 @@ func (r *jwkKeysetResource) Create(ctx context.Context, req resource.CreateReque
 
 // Read
 func (r *jwkKeysetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model KeysetModel
+
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	selected, diags := r.selectKeys(ctx, model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Verify the keys are still consistent
+	for _, key := range selected {
+		if err := checkKeyIntegrity(key.key); err != nil {
+			resp.Diagnostics.AddAttributeError(key.at, "Invalid JWK in state",
+				fmt.Sprintf("integrity check failed for key '%s': %s", key.key.KeyID(), err.Error()))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	keys := keysetKeysList(selected)
+
+	// Key sets are recomputed from the keys, so that drift shows up in plan
+	keysetJSON, err := createJWKKeyset(keys)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid keys in state", err.Error())
+		return
+	}
+
+	publicKeysetJSON, err := createPublicJWKKeyset(keys, model.octKeyHandling())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid keys in state", err.Error())
+		return
+	}
+
+	stored := model.KeysetJSON.ValueString()
+	storedJSON, err := r.providerData.encryption().open(stored)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to decrypt JWK Keyset in state", err.Error())
+		return
+	}
+
+	// Encryption is not deterministic, so the key set is encrypted again only, when it
+	// has changed, or was stored before state encryption was enabled
+	if storedJSON != keysetJSON || (r.providerData.encryption().enabled() && !isSealed(stored)) {
+		sealedJSON, err := r.providerData.encryption().seal(keysetJSON)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to encrypt JWK Keyset for state", err.Error())
+			return
+		}
+		model.KeysetJSON = types.StringValue(sealedJSON)
+	}
+	model.PublicKeysetJSON = types.StringValue(publicKeysetJSON)
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Update
@@ -85,13 +280,45 @@ func (r *jwkKeysetResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	KeysetJSON, err := createJWKKeyset(model.Keys)
+	selected, diags := r.selectKeys(ctx, model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	keys := keysetKeysList(selected)
+
+	// Key IDs may have been unknown during validation
+	if err := checkDuplicateKIDs(keys); err != nil {
+		resp.Diagnostics.AddError("Duplicate key id", err.Error())
+		return
+	}
+
+	// Keys may have been unknown during plan
+	resp.Diagnostics.Append(r.checkKeyPolicy(selected)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	KeysetJSON, err := createJWKKeyset(keys)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to Create JWK Keysset", err.Error())
 		return
 	}
 
-	model.KeysetJSON = types.StringValue(KeysetJSON)
+	PublicKeysetJSON, err := createPublicJWKKeyset(keys, model.octKeyHandling())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create public JWK Keyset", err.Error())
+		return
+	}
+
+	sealedJSON, err := r.providerData.encryption().seal(KeysetJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt JWK Keyset for state", err.Error())
+		return
+	}
+
+	model.KeysetJSON = types.StringValue(sealedJSON)
+	model.PublicKeysetJSON = types.StringValue(PublicKeysetJSON)
 	diags = resp.State.Set(ctx, model)
 	resp.Diagnostics.Append(diags...)
 }
@@ -100,6 +327,60 @@ func (r *jwkKeysetResource) Update(ctx context.Context, req resource.UpdateReque
 func (r *jwkKeysetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 }
 
+// ModifyPlan checks the keys known during plan against the policy of the provider
+func (r *jwkKeysetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var model KeysetModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() || model.Keys.IsUnknown() || model.SourceKeysets.IsUnknown() || !model.selectionKnown() {
+		return
+	}
+
+	selected, diags := r.selectKeys(ctx, model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.checkKeyPolicy(selected)...)
+}
+
+// Keys of the key set, merged from 'keys' and 'source_keysets', then filtered, de-duplicated and sorted
+func (r *jwkKeysetResource) selectKeys(ctx context.Context, model KeysetModel) ([]keysetKey, diag.Diagnostics) {
+	// Keys may be encrypted by state encryption
+	keys, diags := r.providerData.encryption().openKeysetKeys(model)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	selection, d := newKeysetSelection(ctx, model)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	selected, err := selection.apply(keys)
+	if err != nil {
+		diags.AddError("Failed to select keys", err.Error())
+		return nil, diags
+	}
+	return selected, diags
+}
+
+// Check the keys against the policy of the provider, reporting violations on the attribute the key is given in
+func (r *jwkKeysetResource) checkKeyPolicy(keys []keysetKey) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, key := range keys {
+		diags.Append(r.providerData.keyPolicy().checkKeyAt(policyKeyOf(key.key), key.at)...)
+	}
+
+	return diags
+}
+
 func (r jwkKeysetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
 	var model KeysetModel
 
@@ -108,6 +389,27 @@ func (r jwkKeysetResource) ValidateConfig(ctx context.Context, req resource.Vali
 		return
 	}
 
+	if model.Keys.IsNull() && model.SourceKeysets.IsNull() {
+		resp.Diagnostics.AddError("Missing keys", "Give 'keys', 'source_keysets' or both.")
+	}
+
+	if model.Filter != nil {
+		validateKeysetFilter(model.Filter, &resp.Diagnostics)
+	}
+
+	// Encrypted key sets are checked, when they are decrypted during apply
+	for i, value := range model.SourceKeysets.Elements() {
+		keysetStr, ok := value.(types.String)
+		if !ok || keysetStr.IsUnknown() || keysetStr.IsNull() || isSealed(keysetStr.ValueString()) {
+			continue
+		}
+		if _, _, err := parseJWKSet([]byte(keysetStr.ValueString())); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("source_keysets").AtListIndex(i), "Invalid key set", err.Error())
+		}
+	}
+
+	// Duplicate key ids may be removed by filtering or de-duplication, so they are checked during apply
+	checkKids := model.Filter == nil && !model.Deduplicate.IsUnknown() && !model.Deduplicate.ValueBool()
 	seenKids := make(map[string]bool)
 
 	for _, keyJSON := range model.Keys.Elements() {
@@ -120,6 +422,11 @@ func (r jwkKeysetResource) ValidateConfig(ctx context.Context, req resource.Vali
 			continue
 		}
 
+		// Encrypted keys are checked, when they are decrypted during apply
+		if keyStr, ok := keyJSON.(types.String); ok && isSealed(keyStr.ValueString()) {
+			continue
+		}
+
 		jsonStr := keyJSON.String()
 		if jsonStr == "" {
 			resp.Diagnostics.AddError("Invalid Key", "Key value is empty")
@@ -132,10 +439,31 @@ func (r jwkKeysetResource) ValidateConfig(ctx context.Context, req resource.Vali
 			continue
 		}
 
-		if seenKids[key.KeyID()] {
+		if checkKids && seenKids[key.KeyID()] {
 			resp.Diagnostics.AddError("Duplicate key id", "Duplicate key id (kid) "+key.KeyID())
 		}
 		seenKids[key.KeyID()] = true
 	}
 
 }
+
+// Validate the known values of the filter. 'use' is checked by the validator of the attribute.
+func validateKeysetFilter(filter *keysetFilterModel, diags *diag.Diagnostics) {
+	root := path.Root("filter")
+
+	for i, value := range filter.Algorithms.Elements() {
+		alg, ok := value.(types.String)
+		if ok && !alg.IsUnknown() && !isValid(alg.ValueString(), knownAlgorithms()) {
+			diags.AddAttributeError(root.AtName("alg").AtListIndex(i), "Invalid algorithm in 'alg'",
+				fmt.Sprintf("Expected one of %v, got '%s'", knownAlgorithms(), alg.ValueString()))
+		}
+	}
+
+	for i, value := range filter.KeyTypes.Elements() {
+		kty, ok := value.(types.String)
+		if ok && !kty.IsUnknown() && !isValid(kty.ValueString(), validKeyTypes) {
+			diags.AddAttributeError(root.AtName("kty").AtListIndex(i), "Invalid key type in 'kty'",
+				fmt.Sprintf("Expected one of %v, got '%s'", validKeyTypes, kty.ValueString()))
+		}
+	}
+}
@@ -0,0 +1,611 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+)
+
+// Octet key pair (OKP) constants, see RFC 8037
+
+// On signing, EdDSA can be used with any Edwards curve, while the
+// fully specified algorithms (RFC 9864) require a specific curve
+var OKPSigningAlgorithmsToCurves = map[string]string{
+	"Ed25519": "Ed25519",
+	"Ed448":   "Ed448",
+}
+
+// Allowed signing algorithms
+var OKPSigAlgorithms = map[string]int{
+	"EdDSA":   0, // Size is determined by the curve
+	"Ed25519": 256,
+	"Ed448":   448,
+}
+
+// Curves usable for signing (EdDSA)
+var validOKPSigningCurves = []string{
+	"Ed25519", "Ed448",
+}
+
+// Curves usable for key agreement (ECDH-ES), see ECEncAlgorithms for allowed algorithms
+var validOKPEncryptionCurves = []string{
+	"X25519", "X448",
+}
+
+// Creates a new instance of the jwkOKPKeyResource.
+func NewJwkOKPKeyResource() resource.Resource {
+	return &jwkOKPKeyResource{}
+}
+
+// jwkOKPKeyResource is a custom resource that generates a JSON Web Key (JWK) in OKP format.
+type jwkOKPKeyResource struct {
+	providerData *jwkProviderData
+}
+
+// This struct gets populated with the configuration values
+type jwkOKPKeyModel struct {
+	KID         types.String `tfsdk:"kid"`
+	KIDStrategy types.String `tfsdk:"kid_strategy"`
+	Use         types.String `tfsdk:"use"`
+	Crv         types.String `tfsdk:"crv"`
+	Alg         types.String `tfsdk:"alg"`
+	Keepers     types.Map    `tfsdk:"keepers"`
+	KeyJSON     types.String `tfsdk:"json"`
+	Thumbprint  types.String `tfsdk:"thumbprint"`
+}
+
+// Resource Documentation
+func (r *jwkOKPKeyResource) Documentation() string {
+	return `This resource creates and manages Octet Key Pair (OKP) keys for JSON Web Key (JWK) purposes, as defined in RFC 8037.
+Edwards curve keys ('Ed25519', 'Ed448') are used to sign ('sig') data with EdDSA, and Montgomery curve keys
+('X25519', 'X448') are used to encrypt ('enc') data with ECDH-ES key agreement.
+The 'kid' field specifies the unique identifier for the key, while the 'use' field determines
+whether the key is used for signing or encryption. The 'alg' field defines the signing or
+encryption algorithm to be used, and the 'crv' field specifies the curve to be used.`
+}
+
+// Resource Metadata
+func (r *jwkOKPKeyResource) Metadata(_ context.Context, _ resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "jwk_okp_key"
+}
+
+// Resource Configure
+func (r *jwkOKPKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.providerData = resourceProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+// Resource Schema
+func (r *jwkOKPKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	sigAlgs := keys(OKPSigAlgorithms)
+	encAlgs := keys(ECEncAlgorithms)
+
+	resp.Schema = schema.Schema{
+		Description: r.Documentation(),
+
+		Attributes: map[string]schema.Attribute{
+			"kid": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "The Key ID (KID) is a unique identifier for the key. It is used to distinguish different keys in a key set. " +
+					"If not given, the Key ID is generated according to `kid_strategy`.",
+				PlanModifiers: []planmodifier.String{kidPlanModifier{}},
+			},
+			"kid_strategy": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"Strategy used to generate the Key ID, when `kid` is not given. `%s`. Defaults to `%s`, the RFC 7638 "+
+						"JWK Thumbprint (base64url encoded SHA-256), which can be verified by anyone holding the key.",
+					strings.Join(validKIDStrategies, "`, `"), defaultKIDStrategy,
+				),
+				Validators: []validator.String{stringOneOf(validKIDStrategies...)},
+			},
+			"use": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Specifies the intended use of the key. Allowed values: `sig` (for signing) and `enc` (for encryption). " +
+					"Required, unless `use` is given in `defaults` of the provider.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+				Validators:    []validator.String{stringOneOf(validUses...)},
+			},
+			"crv": schema.StringAttribute{
+				Required: true,
+				Description: fmt.Sprintf(
+					"Curve used for the key. `%s` for signing, `%s` for encryption. Changing it replaces the key.",
+					strings.Join(validOKPSigningCurves, "`, `"), strings.Join(validOKPEncryptionCurves, "`, `"),
+				),
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				Validators:    []validator.String{stringOneOf(append(append([]string{}, validOKPSigningCurves...), validOKPEncryptionCurves...)...)},
+			},
+			"alg": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: fmt.Sprintf(
+					"The cryptographic algorithm associated with the key. `%s` for signing, `%s` for encryption. "+
+						"Defaults to the algorithm for `OKP` in `defaults` of the provider, when it suits `use`.",
+					strings.Join(sigAlgs, "`, `"), strings.Join(encAlgs, "`, `"),
+				),
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"keepers": keepersAttribute(),
+			"thumbprint": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The RFC 7638 JWK Thumbprint of the key (base64url encoded SHA-256). It is not sensitive, so changes of the key are visible in plans and outputs.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"json": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+				Description: "The JSON representation of the key in JWK (JSON Web Key) format. This value is automatically generated. " +
+					"Encrypted as a JWE, when `state_encryption` is configured in the provider, see the `decrypt_state` function.",
+				PlanModifiers: []planmodifier.String{useStateWhenUnchanged("kid", "use", "alg")},
+			},
+		},
+	}
+}
+
+// Create is identical to Update, so we could reuse some code here
+func (r *jwkOKPKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model jwkOKPKeyModel
+
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := generateOKPJWK(model.KID.ValueString(), model.Use.ValueString(), model.Alg.ValueString(), model.Crv.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("OKP Key Generation Failed", err.Error())
+		return
+	}
+
+	if err := r.providerData.keyDefaults().setComputedKID(key, model.KID, model.KIDStrategy); err != nil {
+		resp.Diagnostics.AddError("Key ID Generation Failed", err.Error())
+		return
+	}
+	model.KID = types.StringValue(key.KeyID())
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
+		return
+	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create OKP key", err.Error())
+		return
+	}
+
+	sealedJSON, err := r.providerData.encryption().seal(string(keyJSON))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt OKP key for state", err.Error())
+		return
+	}
+	model.KeyJSON = types.StringValue(sealedJSON)
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update keeps the key material, as changes of it replace the key. Only the metadata is updated.
+func (r *jwkOKPKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model, state jwkOKPKeyModel
+
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The key is kept as it is, when no attribute of it changed, e.g. only 'kid_strategy'
+	if !model.KeyJSON.IsUnknown() {
+		diags = resp.State.Set(ctx, model)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	stateJSON, err := r.providerData.encryption().open(state.KeyJSON.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to decrypt JWK in state", err.Error())
+		return
+	}
+
+	key, err := json2jwk(stateJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK in state", err.Error())
+		return
+	}
+
+	if err := updateKeyMetadata(key, model.KID, model.Use, model.Alg); err != nil {
+		resp.Diagnostics.AddError("Key Update Failed", err.Error())
+		return
+	}
+
+	if err := r.providerData.keyDefaults().setComputedKID(key, model.KID, model.KIDStrategy); err != nil {
+		resp.Diagnostics.AddError("Key ID Generation Failed", err.Error())
+		return
+	}
+
+	model.KID = types.StringValue(key.KeyID())
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
+		return
+	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create OKP key", err.Error())
+		return
+	}
+
+	sealedJSON, err := r.providerData.encryption().seal(string(keyJSON))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt OKP key for state", err.Error())
+		return
+	}
+	model.KeyJSON = types.StringValue(sealedJSON)
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *jwkOKPKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// ImportState
+func (r *jwkOKPKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Read imported JWK Json
+	var jwk map[string]interface{}
+	if err := json.Unmarshal([]byte(req.ID), &jwk); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid JWK JSON",
+			fmt.Sprintf("Could not parse imported JWK: %s", err.Error()),
+		)
+		return
+	}
+
+	// Check mandatory fields. Key ID is derived from the key, when not given
+	keyJSON := req.ID
+	kid, ok := jwk["kid"].(string)
+	if !ok {
+		var err error
+		kid, keyJSON, err = thumbprintKID(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Missing Key ID",
+				fmt.Sprintf("Could not derive Key ID of imported JWK: %s", err.Error()),
+			)
+			return
+		}
+	}
+
+	use, ok := jwk["use"].(string)
+	if !ok || (use != "sig" && use != "enc") {
+		resp.Diagnostics.AddError(
+			"Missing or invalid Use",
+			"Imported JWK must contain valid 'use' field ('sig' or 'enc')",
+		)
+		return
+	}
+
+	crv, ok := jwk["crv"].(string)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Missing Curve",
+			"Imported OKP JWK must contain 'crv' field",
+		)
+		return
+	}
+
+	// Make sure it is an OKP key
+	if kty, ok := jwk["kty"].(string); !ok || kty != "OKP" {
+		resp.Diagnostics.AddError(
+			"Invalid Key Type",
+			"Imported JWK must be of type 'OKP'",
+		)
+		return
+	}
+
+	// Handle optional algorithm
+	alg := ""
+	if a, ok := jwk["alg"].(string); ok {
+		alg = a
+	}
+
+	if use == "sig" {
+		if !isValid(crv, validOKPSigningCurves) {
+			resp.Diagnostics.AddError(
+				"Invalid Curve",
+				fmt.Sprintf("Unsupported signing curve '%s'. Valid curves are: %v", crv, validOKPSigningCurves),
+			)
+			return
+		}
+
+		if alg != "" {
+			expectedCrv, exists := OKPSigningAlgorithmsToCurves[alg]
+			if exists && crv != expectedCrv {
+				resp.Diagnostics.AddError(
+					"Incompatible Algorithm and Curve",
+					fmt.Sprintf("Algorithm '%s' requires curve '%s'", alg, expectedCrv),
+				)
+				return
+			}
+		}
+	} else if use == "enc" {
+		if !isValid(crv, validOKPEncryptionCurves) {
+			resp.Diagnostics.AddError(
+				"Invalid Curve",
+				fmt.Sprintf("Unsupported encryption curve '%s'. Valid curves are: %v", crv, validOKPEncryptionCurves),
+			)
+			return
+		}
+
+		if alg != "" {
+			_, exists := ECEncAlgorithms[alg]
+			if !exists {
+				resp.Diagnostics.AddError(
+					"Invalid Encryption Algorithm",
+					fmt.Sprintf("Unsupported encryption algorithm '%s'", alg),
+				)
+				return
+			}
+		}
+	}
+
+	thumbprint, err := jsonThumbprint(keyJSON)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid JWK JSON",
+			fmt.Sprintf("Could not compute thumbprint of imported JWK: %s", err.Error()),
+		)
+		return
+	}
+
+	sealedJSON, err := r.providerData.encryption().seal(keyJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt imported JWK for state", err.Error())
+		return
+	}
+
+	model := jwkOKPKeyModel{
+		KID:        types.StringValue(kid),
+		Use:        types.StringValue(use),
+		Crv:        types.StringValue(crv),
+		Alg:        types.StringValue(alg),
+		Keepers:    types.MapNull(types.StringType),
+		KeyJSON:    types.StringValue(sealedJSON),
+		Thumbprint: types.StringValue(thumbprint),
+	}
+
+	// Store model to state
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// Read
+func (r *jwkOKPKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model jwkOKPKeyModel
+
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	stored := model.KeyJSON.ValueString()
+	keyJSON, err := r.providerData.encryption().open(stored)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to decrypt JWK in state", err.Error())
+		return
+	}
+
+	// Verify the key is still valid and consistent
+	key, err := parseStateKey(keyJSON, jwa.OKP)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK in state", err.Error())
+		return
+	}
+
+	// Attributes follow the key, so that drift shows up in plan
+	model.KID = keyAttributeValue(model.KID, key.KeyID())
+	model.Use = keyAttributeValue(model.Use, key.KeyUsage())
+	model.Alg = keyAttributeValue(model.Alg, key.Algorithm().String())
+	model.Crv = keyAttributeValue(model.Crv, keyCurve(key))
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
+		return
+	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	// Encrypt keys stored before state encryption was enabled
+	if r.providerData.encryption().enabled() && !isSealed(stored) {
+		sealedJSON, err := r.providerData.encryption().seal(keyJSON)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to encrypt JWK in state", err.Error())
+			return
+		}
+		model.KeyJSON = types.StringValue(sealedJSON)
+	}
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+// -----------------------------------------------------------------------------
+// ---    Plan Modification    -------------------------------------------------
+// -----------------------------------------------------------------------------
+
+// ModifyPlan applies the defaults of the provider to the attributes not given in the
+// configuration, and checks the resulting key specification.
+func (r *jwkOKPKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, plan jwkOKPKeyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	defaults := r.providerData.keyDefaults()
+	plan.Use = defaultString(config.Use, plan.Use, defaults.useValue())
+	plan.Alg = defaultString(config.Alg, plan.Alg, defaults.algValue("OKP", plan.Use))
+
+	if plan.Use.IsNull() {
+		addMissingDefaultError(&resp.Diagnostics, "use", "use")
+		return
+	}
+
+	// Checks skipped in ValidateConfig, as the values were not known without the defaults
+	if (config.Use.IsNull() || config.Alg.IsNull()) && !plan.Use.IsUnknown() && !plan.Alg.IsUnknown() {
+		check := plan
+		check.KID = config.KID
+		resp.Diagnostics.Append(validateOKPKeyConfig(check)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Policy of the provider, checked with the defaults applied
+	if !plan.Use.IsUnknown() && !plan.Alg.IsUnknown() && !plan.Crv.IsUnknown() {
+		resp.Diagnostics.Append(r.providerData.keyPolicy().checkKey(policyKey{
+			kty: "OKP",
+			use: plan.Use.ValueString(),
+			alg: plan.Alg.ValueString(),
+			crv: plan.Crv.ValueString(),
+		})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state jwkOKPKeyModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Changed defaults update the key like changes in configuration
+		if !plan.Use.Equal(state.Use) || !plan.Alg.Equal(state.Alg) {
+			plan.KeyJSON = types.StringUnknown()
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+// -----------------------------------------------------------------------------
+// ---    Validate Configuration    --------------------------------------------
+// -----------------------------------------------------------------------------
+
+func (r jwkOKPKeyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model jwkOKPKeyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Attributes not given may be set from the defaults of the provider, and are checked in ModifyPlan
+	if model.Use.IsNull() || model.Alg.IsNull() {
+		resp.Diagnostics.Append(validateKIDConfig(model.KID, model.KIDStrategy)...)
+		return
+	}
+
+	resp.Diagnostics.Append(validateOKPKeyConfig(model)...)
+}
+
+// Validate the configuration of an OKP key
+func validateOKPKeyConfig(model jwkOKPKeyModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	diags.Append(validateKIDConfig(model.KID, model.KIDStrategy)...)
+
+	// 'use' is checked by the validator of the attribute, 'alg' and 'crv' depend on it
+	if model.Use.IsUnknown() {
+		return diags
+	}
+
+	crv := model.Crv.ValueString()
+	alg := model.Alg.ValueString()
+
+	switch model.Use.ValueString() {
+	case "sig":
+		// Check crv, only Edwards curves can sign
+		crvValid := !model.Crv.IsUnknown() && isValid(crv, validOKPSigningCurves)
+		if !model.Crv.IsUnknown() && !crvValid {
+			diags.AddAttributeError(
+				path.Root("crv"),
+				"Invalid 'crv' attribute for use: 'sig'",
+				fmt.Sprintf("Expected one of '%s', got '%s'", strings.Join(validOKPSigningCurves, ", "), crv),
+			)
+		}
+
+		// Check, alg is allowed on 'sig'
+		if model.Alg.IsUnknown() || alg == "" {
+			break
+		}
+		if _, exists := OKPSigAlgorithms[alg]; !exists {
+			diags.AddAttributeError(
+				path.Root("alg"),
+				"Invalid 'alg' attribute for use: 'sig'",
+				fmt.Sprintf("Expected one of %s, got %s", keys(OKPSigAlgorithms), alg),
+			)
+		} else if expectedCrv, exists := OKPSigningAlgorithmsToCurves[alg]; exists && crvValid && crv != expectedCrv {
+			// crv needs to match fully specified signing algorithm
+			diags.AddAttributeError(
+				path.Root("crv"),
+				"Inconsistent 'crv' for given 'alg'",
+				fmt.Sprintf("Algorithm '%s' requires curve '%s', but got '%s'", alg, expectedCrv, crv),
+			)
+		}
+	case "enc":
+		// Check crv, only Montgomery curves can be used in key agreement
+		if !model.Crv.IsUnknown() && !isValid(crv, validOKPEncryptionCurves) {
+			diags.AddAttributeError(
+				path.Root("crv"),
+				"Invalid 'crv' attribute for use: 'enc'",
+				fmt.Sprintf("Expected one of '%s', got '%s'", strings.Join(validOKPEncryptionCurves, ", "), crv),
+			)
+		}
+
+		// Check, alg is allowed on 'enc'
+		if model.Alg.IsUnknown() || alg == "" {
+			break
+		}
+		if _, exists := ECEncAlgorithms[alg]; !exists {
+			diags.AddAttributeError(
+				path.Root("alg"),
+				"Invalid 'alg' attribute for use: 'enc'",
+				fmt.Sprintf("Expected one of %s, got %s", keys(ECEncAlgorithms), alg),
+			)
+		}
+	}
+
+	return diags
+}
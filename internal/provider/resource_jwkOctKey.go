@@ -7,9 +7,16 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwa"
 )
 
 // Constants for valid algorithms
@@ -31,15 +38,20 @@ func NewJwkOctKeyResource() resource.Resource {
 }
 
 // jwkOctKeyResource is a custom resource that generates a JSON Web Key (JWK) in Oct format.
-type jwkOctKeyResource struct{}
+type jwkOctKeyResource struct {
+	providerData *jwkProviderData
+}
 
 // This struct gets populated with the configuration values
 type jwkOctKeyModel struct {
-	KID        types.String `tfsdk:"kid"`
-	Use        types.String `tfsdk:"use"`
-	Alg        types.String `tfsdk:"alg"`
-	Size       types.Int64  `tfsdk:"size"`
-	OctKeyJSON types.String `tfsdk:"json"`
+	KID         types.String `tfsdk:"kid"`
+	KIDStrategy types.String `tfsdk:"kid_strategy"`
+	Use         types.String `tfsdk:"use"`
+	Alg         types.String `tfsdk:"alg"`
+	Size        types.Int64  `tfsdk:"size"`
+	Keepers     types.Map    `tfsdk:"keepers"`
+	OctKeyJSON  types.String `tfsdk:"json"`
+	Thumbprint  types.String `tfsdk:"thumbprint"`
 }
 
 // Resource Documentation
@@ -52,6 +64,11 @@ func (r *jwkOctKeyResource) Metadata(_ context.Context, _ resource.MetadataReque
 	resp.TypeName = "jwk_oct_key"
 }
 
+// Resource Configure
+func (r *jwkOctKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.providerData = resourceProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
 // Resource Schema
 func (r *jwkOctKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	sigAlgs := keys(OCTSignatureAlgorithms)
@@ -62,34 +79,74 @@ func (r *jwkOctKeyResource) Schema(_ context.Context, _ resource.SchemaRequest,
 
 		Attributes: map[string]schema.Attribute{
 			"kid": schema.StringAttribute{
-				Required:    true,
-				Description: "The Key ID (KID) is a unique identifier for the key. It is used to distinguish different keys in a key set.",
+				Optional: true,
+				Computed: true,
+				Description: "The Key ID (KID) is a unique identifier for the key. It is used to distinguish different keys in a key set. " +
+					"If not given, the Key ID is generated according to `kid_strategy`.",
+				PlanModifiers: []planmodifier.String{kidPlanModifier{}},
+			},
+			"kid_strategy": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"Strategy used to generate the Key ID, when `kid` is not given. `%s`. Defaults to `%s`, the RFC 7638 "+
+						"JWK Thumbprint (base64url encoded SHA-256), which can be verified by anyone holding the key.",
+					strings.Join(validKIDStrategies, "`, `"), defaultKIDStrategy,
+				),
+				Validators: []validator.String{stringOneOf(validKIDStrategies...)},
 			},
 			"use": schema.StringAttribute{
-				Required:    true,
-				Description: "Specifies the intended use of the key. Allowed values: `sig` (for signing) and `enc` (for encryption).",
+				Optional: true,
+				Computed: true,
+				Description: "Specifies the intended use of the key. Allowed values: `sig` (for signing) and `enc` (for encryption). " +
+					"Required, unless `use` is given in `defaults` of the provider.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+				Validators:    []validator.String{stringOneOf(validUses...)},
 			},
 			"size": schema.Int64Attribute{
-				Required:    true,
-				Description: "The size of the key in bits. The size needs to be divisible by 8. You can use Terraform to calcualte bit count for you, like 32 * 8. This provides length of 32 bytes (256 bits)",
+				Required:      true,
+				Description:   "The size of the key in bits. The size needs to be divisible by 8. You can use Terraform to calcualte bit count for you, like 32 * 8. This provides length of 32 bytes (256 bits). Changing it replaces the key.",
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+				Validators:    []validator.Int64{int64MultipleOf(8)},
 			},
 			"alg": schema.StringAttribute{
 				Optional: true,
+				Computed: true,
 				Description: fmt.Sprintf(
-					"The cryptographic algorithm associated with the key. `%s` for signing, `%s` for encryption",
+					"The cryptographic algorithm associated with the key. `%s` for signing, `%s` for encryption. "+
+						"Defaults to the algorithm for `oct` in `defaults` of the provider, when it suits `use`. "+
+						"Changing it from or to `none` replaces the key.",
 					strings.Join(sigAlgs, "`, `"), strings.Join(encAlgs, "`, `"),
 				),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIf(requiresReplaceIfAlgNone, algNoneReplaceDescription, algNoneReplaceDescription),
+				},
 			},
+			"keepers": keepersAttribute(),
 
+			"thumbprint": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The RFC 7638 JWK Thumbprint of the key (base64url encoded SHA-256). It is not sensitive, so changes of the key are visible in plans and outputs.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
 			"json": schema.StringAttribute{
-				Computed:    true,
-				Sensitive:   true,
-				Description: "The JSON representation of the key in JWK (JSON Web Key) format. This value is automatically generated.",
+				Computed:  true,
+				Sensitive: true,
+				Description: "The JSON representation of the key in JWK (JSON Web Key) format. This value is automatically generated. " +
+					"Encrypted as a JWE, when `state_encryption` is configured in the provider, see the `decrypt_state` function.",
+				PlanModifiers: []planmodifier.String{useStateWhenUnchanged("kid", "use", "alg")},
 			},
 		},
 	}
 }
 
+const algNoneReplaceDescription = "Keys with algorithm `none` have no key material, so changes from or to `none` replace the key."
+
+// Keys with algorithm 'none' are created with a placeholder of 1 byte, so they cannot be updated in place
+func requiresReplaceIfAlgNone(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.PlanValue.IsUnknown() && (req.PlanValue.ValueString() == "none") != (req.StateValue.ValueString() == "none")
+}
+
 // Create is identical to Update, so we could reuse some code here
 func (r *jwkOctKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var model jwkOctKeyModel
@@ -113,13 +170,31 @@ func (r *jwkOctKeyResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	if err := r.providerData.keyDefaults().setComputedKID(key, model.KID, model.KIDStrategy); err != nil {
+		resp.Diagnostics.AddError("Key ID Generation Failed", err.Error())
+		return
+	}
+	model.KID = types.StringValue(key.KeyID())
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
+		return
+	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
 	keyJSON, err := json.Marshal(key)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create symmetric key", err.Error())
 		return
 	}
 
-	model.OctKeyJSON = types.StringValue(string(keyJSON))
+	sealedJSON, err := r.providerData.encryption().seal(string(keyJSON))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt symmetric key for state", err.Error())
+		return
+	}
+	model.OctKeyJSON = types.StringValue(sealedJSON)
 
 	diags = resp.State.Set(ctx, model)
 	resp.Diagnostics.Append(diags...)
@@ -134,47 +209,120 @@ func (r *jwkOctKeyResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	// Verify the key is still valid by parsing it
-	var key map[string]interface{}
-	if err := json.Unmarshal([]byte(model.OctKeyJSON.ValueString()), &key); err != nil {
-		resp.Diagnostics.AddError(
-			"Invalid JWK in state",
-			fmt.Sprintf("Could not parse stored JWK: %s", err.Error()),
-		)
+	stored := model.OctKeyJSON.ValueString()
+	keyJSON, err := r.providerData.encryption().open(stored)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to decrypt JWK in state", err.Error())
 		return
 	}
 
+	// Verify the key is still valid and consistent
+	key, err := parseStateKey(keyJSON, jwa.OctetSeq)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK in state", err.Error())
+		return
+	}
+
+	// Attributes follow the key, so that drift shows up in plan
+	model.KID = keyAttributeValue(model.KID, key.KeyID())
+	model.Use = keyAttributeValue(model.Use, key.KeyUsage())
+	model.Alg = keyAttributeValue(model.Alg, key.Algorithm().String())
+
+	// Keys with algorithm 'none' have a placeholder of 1 byte, regardless of the size
+	if model.Alg.ValueString() != "none" {
+		var raw []byte
+		if err := key.Raw(&raw); err != nil {
+			resp.Diagnostics.AddError("Invalid JWK in state", err.Error())
+			return
+		}
+		if bits := int64(len(raw) * 8); bits != model.Size.ValueInt64() {
+			model.Size = types.Int64Value(bits)
+		}
+	}
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
+		return
+	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	// Encrypt keys stored before state encryption was enabled
+	if r.providerData.encryption().enabled() && !isSealed(stored) {
+		sealedJSON, err := r.providerData.encryption().seal(keyJSON)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to encrypt JWK in state", err.Error())
+			return
+		}
+		model.OctKeyJSON = types.StringValue(sealedJSON)
+	}
+
 	// Update any computed values if needed
 	diags = resp.State.Set(ctx, model)
 	resp.Diagnostics.Append(diags...)
 }
 
-// Update is identical to Create, so we could reuse some code here
+// Update keeps the key material, as changes of it replace the key. Only the metadata is updated.
 func (r *jwkOctKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var model jwkOctKeyModel
+	var model, state jwkOctKeyModel
 
 	diags := req.Plan.Get(ctx, &model)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	num_bytes := int(model.Size.ValueInt64()) / 8 // Number of bytes
-	key, err := generateOctJWK(model.KID.ValueString(), model.Use.ValueString(),
-		model.Alg.ValueString(), num_bytes)
+	// The key is kept as it is, when no attribute of it changed, e.g. only 'kid_strategy'
+	if !model.OctKeyJSON.IsUnknown() {
+		diags = resp.State.Set(ctx, model)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
 
+	stateJSON, err := r.providerData.encryption().open(state.OctKeyJSON.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Symmetric Key Generation Failed", err.Error())
+		resp.Diagnostics.AddError("Failed to decrypt JWK in state", err.Error())
 		return
 	}
 
+	key, err := json2jwk(stateJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK in state", err.Error())
+		return
+	}
+
+	if err := updateKeyMetadata(key, model.KID, model.Use, model.Alg); err != nil {
+		resp.Diagnostics.AddError("Key Update Failed", err.Error())
+		return
+	}
+
+	if err := r.providerData.keyDefaults().setComputedKID(key, model.KID, model.KIDStrategy); err != nil {
+		resp.Diagnostics.AddError("Key ID Generation Failed", err.Error())
+		return
+	}
+
+	model.KID = types.StringValue(key.KeyID())
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
+		return
+	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
 	keyJSON, err := json.Marshal(key)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create symmetric key", err.Error())
 		return
 	}
 
-	model.OctKeyJSON = types.StringValue(string(keyJSON))
+	sealedJSON, err := r.providerData.encryption().seal(string(keyJSON))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt symmetric key for state", err.Error())
+		return
+	}
+	model.OctKeyJSON = types.StringValue(sealedJSON)
 
 	diags = resp.State.Set(ctx, model)
 	resp.Diagnostics.Append(diags...)
@@ -183,6 +331,78 @@ func (r *jwkOctKeyResource) Update(ctx context.Context, req resource.UpdateReque
 func (r *jwkOctKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 }
 
+// -----------------------------------------------------------------------------
+// ---    Plan Modification    -------------------------------------------------
+// -----------------------------------------------------------------------------
+
+// ModifyPlan applies the defaults of the provider to the attributes not given in the
+// configuration, and checks the resulting key specification.
+func (r *jwkOctKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, plan jwkOctKeyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	defaults := r.providerData.keyDefaults()
+	plan.Use = defaultString(config.Use, plan.Use, defaults.useValue())
+	plan.Alg = defaultString(config.Alg, plan.Alg, defaults.algValue("oct", plan.Use))
+
+	if plan.Use.IsNull() {
+		addMissingDefaultError(&resp.Diagnostics, "use", "use")
+		return
+	}
+
+	// Checks skipped in ValidateConfig, as the values were not known without the defaults
+	if (config.Use.IsNull() || config.Alg.IsNull()) && !plan.Use.IsUnknown() && !plan.Alg.IsUnknown() {
+		check := plan
+		check.KID = config.KID
+		resp.Diagnostics.Append(validateOctKeyConfig(check)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Policy of the provider, checked with the defaults applied
+	if !plan.Use.IsUnknown() && !plan.Alg.IsUnknown() && !plan.Size.IsUnknown() {
+		resp.Diagnostics.Append(r.providerData.keyPolicy().checkKey(policyKey{
+			kty:  "oct",
+			use:  plan.Use.ValueString(),
+			alg:  plan.Alg.ValueString(),
+			size: plan.Size.ValueInt64(),
+		})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state jwkOctKeyModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Changed defaults update the key like changes in configuration
+		if (plan.Alg.ValueString() == "none") != (state.Alg.ValueString() == "none") && !plan.Alg.IsUnknown() {
+			resp.RequiresReplace = resp.RequiresReplace.Append(path.Root("alg"))
+		}
+		if !plan.Use.Equal(state.Use) || !plan.Alg.Equal(state.Alg) {
+			plan.OctKeyJSON = types.StringUnknown()
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
 // -----------------------------------------------------------------------------
 // ---    Validate Configuration    --------------------------------------------
 // -----------------------------------------------------------------------------
@@ -196,100 +416,59 @@ func (r jwkOctKeyResource) ValidateConfig(ctx context.Context, req resource.Vali
 		return
 	}
 
-	// Validate 'use' attribute using helper method
-	if !isValid(model.Use.ValueString(), validUses) {
-		resp.Diagnostics.AddError(
-			"Invalid attribute value for 'use'",
-			fmt.Sprintf("Expected 'sig' or 'enc', got '%s'", model.Use.ValueString()),
-		)
+	// Attributes not given may be set from the defaults of the provider, and are checked in ModifyPlan
+	if model.Use.IsNull() || model.Alg.IsNull() {
+		resp.Diagnostics.Append(validateKIDConfig(model.KID, model.KIDStrategy)...)
 		return
 	}
 
-	bits := int(model.Size.ValueInt64())
+	resp.Diagnostics.Append(validateOctKeyConfig(model)...)
+}
 
-	// Validate size is divisible by 8
-	if bits%8 != 0 {
-		resp.Diagnostics.AddError(
-			"Invalid attribute value for 'size'",
-			fmt.Sprintf("size must be divisible by 8, got '%s'", model.Size),
-		)
-		return
+// Validate the configuration of a symmetric key. Shared by the resource and the ephemeral resource.
+func validateOctKeyConfig(model jwkOctKeyModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	diags.Append(validateKIDConfig(model.KID, model.KIDStrategy)...)
+
+	// 'use' and 'size' are checked by the validators of the attributes. If alg is given, check that it is adhering to specification.
+	if model.Use.IsUnknown() || model.Alg.IsUnknown() || model.Alg.ValueString() == "" {
+		return diags
 	}
 
-	// If alg is given, check that it is adhering to specification
-	if !model.Alg.IsNull() && model.Alg.ValueString() != "" {
-		alg := model.Alg.ValueString()
-
-		// Check if algorithm is valid for 'enc' (encryption) use
-		if model.Use.ValueString() == "enc" {
-			requiredSize, ok := OCTSEncryptionAlgorithms[alg]
-			if !ok {
-				resp.Diagnostics.AddError(
-					"Invalid algorithm",
-					fmt.Sprintf("Algorithm '%s' is not a valid encryption algorithm.", alg),
-				)
-				return
-			}
-
-			// Check if the key size matches the required size for encryption
-			if bits < requiredSize {
-				resp.Diagnostics.AddError(
-					"Invalid key size for 'alg'",
-					fmt.Sprintf("For algorithm '%s', the key size must be at least %d bits (%d bytes).", alg, requiredSize, requiredSize/8),
-				)
-				return
-			}
-		}
+	alg := model.Alg.ValueString()
+
+	var algorithms map[string]int
+	var purpose string
+	switch model.Use.ValueString() {
+	case "enc":
+		algorithms, purpose = OCTSEncryptionAlgorithms, "encryption"
+	case "sig":
+		algorithms, purpose = OCTSignatureAlgorithms, "signature"
+	default:
+		return diags
+	}
 
-		// Check if algorithm is valid for 'sig' (signature) use
-		if model.Use.ValueString() == "sig" {
-			requiredSize, ok := OCTSignatureAlgorithms[alg]
-			if !ok {
-				resp.Diagnostics.AddError(
-					"Invalid algorithm",
-					fmt.Sprintf("Algorithm '%s' is not a valid signature algorithm.", alg),
-				)
-				return
-			}
-
-			// Check if the key size matches the required size for signature
-			if bits < requiredSize {
-				resp.Diagnostics.AddError(
-					"Invalid key size for 'alg'",
-					fmt.Sprintf("For algorithm '%s', the key size must be at least %d bits (%d bytes).", alg, requiredSize, requiredSize/8),
-				)
-				return
-			}
-		}
+	requiredSize, ok := algorithms[alg]
+	if !ok {
+		diags.AddAttributeError(
+			path.Root("alg"),
+			"Invalid algorithm",
+			fmt.Sprintf("Algorithm '%s' is not a valid %s algorithm.", alg, purpose),
+		)
+		return diags
 	}
 
-	// Validate minimum size (only warn for sizes below general security recommendation)
-	if model.Alg.ValueString() != "none" && model.Alg.ValueString() != "dir" {
-		// Default security recommendation is 256 bits
-		securityRecommendation := 256
-
-		// Only show warning if below recommendation and not explicitly allowed by algorithm
-		if bits < securityRecommendation {
-			// Check if this is explicitly allowed by algorithm requirements
-			allowed := false
-			if model.Use.ValueString() == "enc" {
-				if size, ok := OCTSEncryptionAlgorithms[model.Alg.ValueString()]; ok && size <= bits {
-					allowed = true
-				}
-			} else if model.Use.ValueString() == "sig" {
-				if size, ok := OCTSignatureAlgorithms[model.Alg.ValueString()]; ok && size <= bits {
-					allowed = true
-				}
-			}
-
-			if !allowed {
-				resp.Diagnostics.AddWarning(
-					"Potentially insecure key size",
-					fmt.Sprintf("General security recommendation is at least %d bits, got '%d' bits", securityRecommendation, bits),
-				)
-			}
-		}
+	// Check if the key size matches the required size of the algorithm
+	if !model.Size.IsUnknown() && model.Size.ValueInt64() < int64(requiredSize) {
+		diags.AddAttributeError(
+			path.Root("size"),
+			"Invalid key size for 'alg'",
+			fmt.Sprintf("For algorithm '%s', the key size must be at least %d bits (%d bytes).", alg, requiredSize, requiredSize/8),
+		)
 	}
+
+	return diags
 }
 
 func (r *jwkOctKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -303,14 +482,19 @@ func (r *jwkOctKeyResource) ImportState(ctx context.Context, req resource.Import
 		return
 	}
 
-	// Validate required fields
+	// Validate required fields. Key ID is derived from the key, when not given
+	keyJSON := req.ID
 	kid, ok := jwk["kid"].(string)
 	if !ok {
-		resp.Diagnostics.AddError(
-			"Missing Key ID",
-			"Imported JWK must contain 'kid' field",
-		)
-		return
+		var err error
+		kid, keyJSON, err = thumbprintKID(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Missing Key ID",
+				fmt.Sprintf("Could not derive Key ID of imported JWK: %s", err.Error()),
+			)
+			return
+		}
 	}
 
 	use, ok := jwk["use"].(string)
@@ -357,13 +541,30 @@ func (r *jwkOctKeyResource) ImportState(ctx context.Context, req resource.Import
 		return
 	}
 
+	thumbprint, err := jsonThumbprint(keyJSON)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid JWK JSON",
+			fmt.Sprintf("Could not compute thumbprint of imported JWK: %s", err.Error()),
+		)
+		return
+	}
+
+	sealedJSON, err := r.providerData.encryption().seal(keyJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt imported JWK for state", err.Error())
+		return
+	}
+
 	// Create the model
 	model := jwkOctKeyModel{
 		KID:        types.StringValue(kid),
 		Use:        types.StringValue(use),
 		Alg:        types.StringValue(alg),
 		Size:       types.Int64Value(int64(size)),
-		OctKeyJSON: types.StringValue(req.ID),
+		Keepers:    types.MapNull(types.StringType),
+		OctKeyJSON: types.StringValue(sealedJSON),
+		Thumbprint: types.StringValue(thumbprint),
 	}
 
 	// Save to state
@@ -5,12 +5,18 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwa"
 )
 
 // RSA constants
@@ -37,15 +43,25 @@ func NewJwkRSAKeyResource() resource.Resource {
 }
 
 // jwkRSAKeyResource is a custom resource that generates a JSON Web Key (JWK) in RSA format.
-type jwkRSAKeyResource struct{}
+type jwkRSAKeyResource struct {
+	providerData *jwkProviderData
+}
 
 // This struct gets populated with the configuration values
 type jwkRSAKeyModel struct {
-	KID        types.String `tfsdk:"kid"`
-	Use        types.String `tfsdk:"use"`
-	Size       types.Int64  `tfsdk:"size"`
-	Alg        types.String `tfsdk:"alg"`
-	RSAKeyJSON types.String `tfsdk:"json"`
+	KID                types.String         `tfsdk:"kid"`
+	KIDStrategy        types.String         `tfsdk:"kid_strategy"`
+	Use                types.String         `tfsdk:"use"`
+	Size               types.Int64          `tfsdk:"size"`
+	Alg                types.String         `tfsdk:"alg"`
+	Keepers            types.Map            `tfsdk:"keepers"`
+	RSAKeyJSON         types.String         `tfsdk:"json"`
+	Thumbprint         types.String         `tfsdk:"thumbprint"`
+	PrivateKeyPEM      types.String         `tfsdk:"private_key_pem"`
+	PrivateKeyPEMPKCS8 types.String         `tfsdk:"private_key_pem_pkcs8"`
+	PublicKeyPEM       types.String         `tfsdk:"public_key_pem"`
+	CertificatePEM     types.String         `tfsdk:"certificate_pem"`
+	Certificate        *jwkCertificateModel `tfsdk:"certificate"`
 }
 
 // Resource Documentation
@@ -62,6 +78,11 @@ func (r *jwkRSAKeyResource) Metadata(_ context.Context, _ resource.MetadataReque
 	resp.TypeName = "jwk_rsa_key"
 }
 
+// Resource Configure
+func (r *jwkRSAKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.providerData = resourceProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
 // Resource Schema
 func (r *jwkRSAKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	sigAlgs := keys(RSASignatureAlgorithms)
@@ -72,30 +93,87 @@ func (r *jwkRSAKeyResource) Schema(_ context.Context, _ resource.SchemaRequest,
 
 		Attributes: map[string]schema.Attribute{
 			"kid": schema.StringAttribute{
-				Required:    true,
-				Description: "The Key ID (KID) is a unique identifier for the key. It is used to distinguish different keys in a key set.",
+				Optional: true,
+				Computed: true,
+				Description: "The Key ID (KID) is a unique identifier for the key. It is used to distinguish different keys in a key set. " +
+					"If not given, the Key ID is generated according to `kid_strategy`.",
+				PlanModifiers: []planmodifier.String{kidPlanModifier{}},
+			},
+			"kid_strategy": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"Strategy used to generate the Key ID, when `kid` is not given. `%s`. Defaults to `%s`, the RFC 7638 "+
+						"JWK Thumbprint (base64url encoded SHA-256), which can be verified by anyone holding the key.",
+					strings.Join(validKIDStrategies, "`, `"), defaultKIDStrategy,
+				),
+				Validators: []validator.String{stringOneOf(validKIDStrategies...)},
 			},
 			"use": schema.StringAttribute{
-				Required:    true,
-				Description: "Specifies the intended use of the key. Allowed values: `sig` (for signing) and `enc` (for encryption).",
+				Optional: true,
+				Computed: true,
+				Description: "Specifies the intended use of the key. Allowed values: `sig` (for signing) and `enc` (for encryption). " +
+					"Required, unless `use` is given in `defaults` of the provider.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+				Validators:    []validator.String{stringOneOf(validUses...)},
 			},
 			"size": schema.Int64Attribute{
-				Required:    true,
-				Description: "The size of the key in bits. For RSA keys, common values are 2048, 3072, or 4096.",
+				Optional: true,
+				Computed: true,
+				Description: "The size of the key in bits. For RSA keys, common values are 2048, 3072, or 4096. Changing it replaces the key. " +
+					"Required, unless `rsa_size` is given in `defaults` of the provider.",
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.UseStateForUnknown(), int64planmodifier.RequiresReplace()},
+				Validators:    []validator.Int64{int64AtLeast(2048)},
 			},
 			"alg": schema.StringAttribute{
 				Optional: true,
+				Computed: true,
 				Description: fmt.Sprintf(
-					"The cryptographic algorithm associated with the key. `%s` for signing, `%s` for encryption",
+					"The cryptographic algorithm associated with the key. `%s` for signing, `%s` for encryption. "+
+						"Defaults to the algorithm for `RSA` in `defaults` of the provider, when it suits `use`.",
 					strings.Join(sigAlgs, "`, `"), strings.Join(encAlgs, "`, `"),
 				),
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"keepers": keepersAttribute(),
+			"thumbprint": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The RFC 7638 JWK Thumbprint of the key (base64url encoded SHA-256). It is not sensitive, so changes of the key are visible in plans and outputs.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"private_key_pem": schema.StringAttribute{
+				Computed:      true,
+				Sensitive:     true,
+				Description:   "The private key in PEM format, PKCS#1 (`RSA PRIVATE KEY`). Null, when `state_encryption` is configured in the provider.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"private_key_pem_pkcs8": schema.StringAttribute{
+				Computed:      true,
+				Sensitive:     true,
+				Description:   "The private key in PEM format, PKCS#8 (`PRIVATE KEY`). Null, when `state_encryption` is configured in the provider.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"public_key_pem": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The public key in PEM format, PKIX SubjectPublicKeyInfo (`PUBLIC KEY`).",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"certificate_pem": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The self-signed certificate in PEM format, when the `certificate` block is given.",
+				PlanModifiers: []planmodifier.String{useStateWhenUnchanged("kid", "use", "alg", "certificate")},
 			},
 			"json": schema.StringAttribute{
-				Computed:    true,
-				Sensitive:   true,
-				Description: "The JSON representation of the key in JWK (JSON Web Key) format. This value is automatically generated.",
+				Computed:  true,
+				Sensitive: true,
+				Description: "The JSON representation of the key in JWK (JSON Web Key) format. This value is automatically generated. " +
+					"Encrypted as a JWE, when `state_encryption` is configured in the provider, see the `decrypt_state` function.",
+				PlanModifiers: []planmodifier.String{useStateWhenUnchanged("kid", "use", "alg", "certificate")},
 			},
 		},
+
+		Blocks: map[string]schema.Block{
+			"certificate": certificateBlock(),
+		},
 	}
 }
 
@@ -119,33 +197,113 @@ func (r *jwkRSAKeyResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	if err := r.providerData.keyDefaults().setComputedKID(key, model.KID, model.KIDStrategy); err != nil {
+		resp.Diagnostics.AddError("Key ID Generation Failed", err.Error())
+		return
+	}
+	model.KID = types.StringValue(key.KeyID())
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
+		return
+	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	model.CertificatePEM = types.StringNull()
+	if model.Certificate != nil {
+		certPEM, err := addSelfSignedCertificate(ctx, key, model.Certificate)
+		if err != nil {
+			resp.Diagnostics.AddError("Certificate Generation Failed", err.Error())
+			return
+		}
+		model.CertificatePEM = types.StringValue(certPEM)
+	}
+
 	keyJSON, err := json.Marshal(key)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create RSA key", err.Error())
 		return
 	}
 
-	model.RSAKeyJSON = types.StringValue(string(keyJSON))
+	sealedJSON, err := r.providerData.encryption().seal(string(keyJSON))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt RSA key for state", err.Error())
+		return
+	}
+	model.RSAKeyJSON = types.StringValue(sealedJSON)
+
+	privatePEM, pkcs8PEM, publicPEM, err := keyPEMs(key, pemFormatPKCS1)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode RSA key as PEM", err.Error())
+		return
+	}
+	model.PrivateKeyPEM = r.providerData.encryption().privateValue(privatePEM)
+	model.PrivateKeyPEMPKCS8 = r.providerData.encryption().privateValue(pkcs8PEM)
+	model.PublicKeyPEM = types.StringValue(publicPEM)
 
 	diags = resp.State.Set(ctx, model)
 	resp.Diagnostics.Append(diags...)
 }
 
-// Update is identical to Create, so we could reuse some code here
+// Update keeps the key material, as changes of it replace the key. Only the metadata is updated.
 func (r *jwkRSAKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var model jwkRSAKeyModel
+	var model, state jwkRSAKeyModel
 
 	diags := req.Plan.Get(ctx, &model)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	key, err := generateRSAJWK(model.KID.ValueString(), model.Use.ValueString(), model.Alg.ValueString(), int(model.Size.ValueInt64()))
+	// The key is kept as it is, when no attribute of it changed, e.g. only 'kid_strategy'
+	if !model.RSAKeyJSON.IsUnknown() {
+		diags = resp.State.Set(ctx, model)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	stateJSON, err := r.providerData.encryption().open(state.RSAKeyJSON.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("RSA Key Generation Failed", err.Error())
+		resp.Diagnostics.AddError("Failed to decrypt JWK in state", err.Error())
+		return
+	}
+
+	key, err := json2jwk(stateJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK in state", err.Error())
+		return
+	}
+
+	if err := updateKeyMetadata(key, model.KID, model.Use, model.Alg); err != nil {
+		resp.Diagnostics.AddError("Key Update Failed", err.Error())
+		return
+	}
+
+	if err := r.providerData.keyDefaults().setComputedKID(key, model.KID, model.KIDStrategy); err != nil {
+		resp.Diagnostics.AddError("Key ID Generation Failed", err.Error())
+		return
+	}
+
+	model.KID = types.StringValue(key.KeyID())
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
 		return
 	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	model.CertificatePEM = types.StringNull()
+	if model.Certificate != nil {
+		certPEM, err := addSelfSignedCertificate(ctx, key, model.Certificate)
+		if err != nil {
+			resp.Diagnostics.AddError("Certificate Generation Failed", err.Error())
+			return
+		}
+		model.CertificatePEM = types.StringValue(certPEM)
+	}
 
 	keyJSON, err := json.Marshal(key)
 	if err != nil {
@@ -153,7 +311,21 @@ func (r *jwkRSAKeyResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	model.RSAKeyJSON = types.StringValue(string(keyJSON))
+	sealedJSON, err := r.providerData.encryption().seal(string(keyJSON))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt RSA key for state", err.Error())
+		return
+	}
+	model.RSAKeyJSON = types.StringValue(sealedJSON)
+
+	privatePEM, pkcs8PEM, publicPEM, err := keyPEMs(key, pemFormatPKCS1)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode RSA key as PEM", err.Error())
+		return
+	}
+	model.PrivateKeyPEM = r.providerData.encryption().privateValue(privatePEM)
+	model.PrivateKeyPEMPKCS8 = r.providerData.encryption().privateValue(pkcs8PEM)
+	model.PublicKeyPEM = types.StringValue(publicPEM)
 
 	diags = resp.State.Set(ctx, model)
 	resp.Diagnostics.Append(diags...)
@@ -173,14 +345,19 @@ func (r *jwkRSAKeyResource) ImportState(ctx context.Context, req resource.Import
 		return
 	}
 
-	// Extract required fields
+	// Extract required fields. Key ID is derived from the key, when not given
+	keyJSON := req.ID
 	kid, ok := jwk["kid"].(string)
 	if !ok {
-		resp.Diagnostics.AddError(
-			"Missing Key ID",
-			"Imported JWK must contain 'kid' field",
-		)
-		return
+		var err error
+		kid, keyJSON, err = thumbprintKID(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Missing Key ID",
+				fmt.Sprintf("Could not derive Key ID of imported JWK: %s", err.Error()),
+			)
+			return
+		}
 	}
 
 	use, ok := jwk["use"].(string)
@@ -214,13 +391,59 @@ func (r *jwkRSAKeyResource) ImportState(ctx context.Context, req resource.Import
 		size = len(data) * 8
 	}
 
+	thumbprint, err := jsonThumbprint(keyJSON)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid JWK JSON",
+			fmt.Sprintf("Could not compute thumbprint of imported JWK: %s", err.Error()),
+		)
+		return
+	}
+
+	importedKey, err := json2jwk(keyJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK JSON", err.Error())
+		return
+	}
+
+	privatePEM, pkcs8PEM, publicPEM, err := keyPEMs(importedKey, pemFormatPKCS1)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid JWK JSON",
+			fmt.Sprintf("Could not encode imported JWK as PEM: %s", err.Error()),
+		)
+		return
+	}
+
+	certPEM, err := certificatePEM(importedKey)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK JSON", err.Error())
+		return
+	}
+	certificatePEMValue := types.StringNull()
+	if certPEM != "" {
+		certificatePEMValue = types.StringValue(certPEM)
+	}
+
+	sealedJSON, err := r.providerData.encryption().seal(keyJSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encrypt imported JWK for state", err.Error())
+		return
+	}
+
 	// Create the model
 	model := jwkRSAKeyModel{
-		KID:        types.StringValue(kid),
-		Use:        types.StringValue(use),
-		Alg:        types.StringValue(alg),
-		Size:       types.Int64Value(int64(size)),
-		RSAKeyJSON: types.StringValue(req.ID),
+		KID:                types.StringValue(kid),
+		Use:                types.StringValue(use),
+		Alg:                types.StringValue(alg),
+		Keepers:            types.MapNull(types.StringType),
+		Size:               types.Int64Value(int64(size)),
+		RSAKeyJSON:         types.StringValue(sealedJSON),
+		Thumbprint:         types.StringValue(thumbprint),
+		PrivateKeyPEM:      r.providerData.encryption().privateValue(privatePEM),
+		PrivateKeyPEMPKCS8: r.providerData.encryption().privateValue(pkcs8PEM),
+		PublicKeyPEM:       types.StringValue(publicPEM),
+		CertificatePEM:     certificatePEMValue,
 	}
 
 	// Save to state
@@ -236,15 +459,63 @@ func (r *jwkRSAKeyResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	// Verify the key is still valid by parsing it
-	var key map[string]interface{}
-	if err := json.Unmarshal([]byte(model.RSAKeyJSON.ValueString()), &key); err != nil {
-		resp.Diagnostics.AddError(
-			"Invalid JWK in state",
-			fmt.Sprintf("Could not parse stored JWK: %s", err.Error()),
-		)
+	stored := model.RSAKeyJSON.ValueString()
+	keyJSON, err := r.providerData.encryption().open(stored)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to decrypt JWK in state", err.Error())
+		return
+	}
+
+	// Verify the key is still valid and consistent
+	key, err := parseStateKey(keyJSON, jwa.RSA)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK in state", err.Error())
+		return
+	}
+
+	// Attributes follow the key, so that drift shows up in plan
+	model.KID = keyAttributeValue(model.KID, key.KeyID())
+	model.Use = keyAttributeValue(model.Use, key.KeyUsage())
+	model.Alg = keyAttributeValue(model.Alg, key.Algorithm().String())
+	if bits := int64(rsaKeyBits(key)); bits != model.Size.ValueInt64() {
+		model.Size = types.Int64Value(bits)
+	}
+
+	thumbprint, err := jwkThumbprint(key, defaultThumbprintHash)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute key thumbprint", err.Error())
 		return
 	}
+	model.Thumbprint = types.StringValue(thumbprint)
+
+	privatePEM, pkcs8PEM, publicPEM, err := keyPEMs(key, pemFormatPKCS1)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode RSA key as PEM", err.Error())
+		return
+	}
+	model.PrivateKeyPEM = r.providerData.encryption().privateValue(privatePEM)
+	model.PrivateKeyPEMPKCS8 = r.providerData.encryption().privateValue(pkcs8PEM)
+	model.PublicKeyPEM = types.StringValue(publicPEM)
+
+	certPEM, err := certificatePEM(key)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid JWK in state", err.Error())
+		return
+	}
+	model.CertificatePEM = types.StringNull()
+	if certPEM != "" {
+		model.CertificatePEM = types.StringValue(certPEM)
+	}
+
+	// Encrypt keys stored before state encryption was enabled
+	if r.providerData.encryption().enabled() && !isSealed(stored) {
+		sealedJSON, err := r.providerData.encryption().seal(keyJSON)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to encrypt JWK in state", err.Error())
+			return
+		}
+		model.RSAKeyJSON = types.StringValue(sealedJSON)
+	}
 
 	// Update any computed values if needed
 	diags = resp.State.Set(ctx, model)
@@ -252,91 +523,139 @@ func (r *jwkRSAKeyResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 // -----------------------------------------------------------------------------
-// ---    Validate Configuration    --------------------------------------------
+// ---    Plan Modification    -------------------------------------------------
 // -----------------------------------------------------------------------------
 
-func (r jwkRSAKeyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
-	var model jwkRSAKeyModel
+// ModifyPlan applies the defaults of the provider to the attributes not given in the
+// configuration, and checks the resulting key specification.
+func (r *jwkRSAKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
 
-	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	var config, plan jwkRSAKeyModel
 
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	log.Printf("Validating use attribute: %s", model.Use.ValueString())
+	defaults := r.providerData.keyDefaults()
+	plan.Use = defaultString(config.Use, plan.Use, defaults.useValue())
+	plan.Alg = defaultString(config.Alg, plan.Alg, defaults.algValue("RSA", plan.Use))
+	plan.Size = defaultInt64(config.Size, plan.Size, defaults.rsaSizeValue())
 
-	// Validate 'use' attribute using helper method
-	if !isValid(model.Use.ValueString(), validUses) {
-		resp.Diagnostics.AddError(
-			"Invalid attribute value for 'use'",
-			fmt.Sprintf("Expected 'sig' or 'enc', got '%s'", model.Use.ValueString()),
-		)
+	if plan.Use.IsNull() {
+		addMissingDefaultError(&resp.Diagnostics, "use", "use")
+	}
+	if plan.Size.IsNull() {
+		addMissingDefaultError(&resp.Diagnostics, "size", "rsa_size")
+	}
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Validate, that size is ok
-	bits := int(model.Size.ValueInt64())
+	// Checks skipped in ValidateConfig, as the values were not known without the defaults
+	if (config.Use.IsNull() || config.Alg.IsNull() || config.Size.IsNull()) &&
+		!plan.Use.IsUnknown() && !plan.Alg.IsUnknown() && !plan.Size.IsUnknown() {
+		check := plan
+		check.KID = config.KID
+		resp.Diagnostics.Append(validateRSAKeyConfig(check)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
-	if bits < 2048 { // RSA requires at least 2048
-		resp.Diagnostics.AddError(
-			"Invalid attribute value for 'size'",
-			fmt.Sprintf("size must be at least 2048, got '%s'", model.Size),
-		)
-		return
+	// Policy of the provider, checked with the defaults applied
+	if !plan.Use.IsUnknown() && !plan.Alg.IsUnknown() && !plan.Size.IsUnknown() {
+		resp.Diagnostics.Append(r.providerData.keyPolicy().checkKey(policyKey{
+			kty:  "RSA",
+			use:  plan.Use.ValueString(),
+			alg:  plan.Alg.ValueString(),
+			size: plan.Size.ValueInt64(),
+		})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
-	// Validate 'alg' attribute for "sig" use case
-	if model.Alg.ValueString() != "" && model.Use.ValueString() == "sig" {
-		expectedSize, exists := RSASignatureAlgorithms[model.Alg.ValueString()]
+	if !req.State.Raw.IsNull() {
+		var state jwkRSAKeyModel
 
-		if !exists { // Algorithm is not used
-			resp.Diagnostics.AddError(
-				"Invalid 'alg' attribute for use: 'sig'",
-				fmt.Sprintf("Expected a valid RSA signature algorithm %s, got '%s'",
-					keys(RSASignatureAlgorithms), model.Alg.ValueString()),
-			)
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
 
-		if bits < expectedSize { // If too weak key size, report warning
-			resp.Diagnostics.AddWarning(
-				"Suboptimal RSA key size",
-				fmt.Sprintf("Algorithm '%s' should use at least %d bits. Current size: %d bits.", model.Alg.ValueString(), expectedSize, bits),
-			)
+		// Changed defaults update the key like changes in configuration
+		if !plan.Size.Equal(state.Size) {
+			resp.RequiresReplace = resp.RequiresReplace.Append(path.Root("size"))
+		}
+		if !plan.Use.Equal(state.Use) || !plan.Alg.Equal(state.Alg) {
+			plan.RSAKeyJSON = types.StringUnknown()
+			plan.CertificatePEM = types.StringUnknown()
 		}
 	}
 
-	// Validate 'alg' attribute for "enc" use case
-	if model.Alg.ValueString() != "" && model.Use.ValueString() == "enc" {
-		_, exists := RSAEncryptionAlgorithms[model.Alg.ValueString()]
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
 
-		if !exists {
-			resp.Diagnostics.AddError(
-				"Invalid 'alg' attribute for use: 'enc'",
-				fmt.Sprintf("Expected a valid RSA encryption algorithm %s, got '%s'",
-					keys(RSAEncryptionAlgorithms), model.Alg.ValueString()),
-			)
-			return
-		}
+// -----------------------------------------------------------------------------
+// ---    Validate Configuration    --------------------------------------------
+// -----------------------------------------------------------------------------
+
+func (r jwkRSAKeyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model jwkRSAKeyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateCertificateConfig(ctx, model.Certificate)...)
+
+	// Attributes not given may be set from the defaults of the provider, and are checked in ModifyPlan
+	if model.Use.IsNull() || model.Alg.IsNull() || model.Size.IsNull() {
+		resp.Diagnostics.Append(validateKIDConfig(model.KID, model.KIDStrategy)...)
+		return
 	}
 
-	// Issue warning if 'alg' attribute is missing
-	if model.Alg.ValueString() == "" {
-		if model.Use.ValueString() == "enc" {
-			resp.Diagnostics.AddWarning(
-				"No 'alg' attribute for 'enc' use",
-				fmt.Sprintf("Consider setting a valid encryption algorithm, one of '%s'",
-					keys(RSAEncryptionAlgorithms)),
+	resp.Diagnostics.Append(validateRSAKeyConfig(model)...)
+}
+
+// Validate the configuration of an RSA key. Shared by the resource and the ephemeral resource.
+func validateRSAKeyConfig(model jwkRSAKeyModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	diags.Append(validateKIDConfig(model.KID, model.KIDStrategy)...)
+
+	// 'use' and 'size' are checked by the validators of the attributes, the algorithm depends on 'use'
+	if model.Use.IsUnknown() || model.Alg.IsUnknown() || model.Alg.ValueString() == "" {
+		return diags
+	}
+
+	alg := model.Alg.ValueString()
+	switch model.Use.ValueString() {
+	case "sig":
+		if _, exists := RSASignatureAlgorithms[alg]; !exists {
+			diags.AddAttributeError(
+				path.Root("alg"),
+				"Invalid 'alg' attribute for use: 'sig'",
+				fmt.Sprintf("Expected a valid RSA signature algorithm %s, got '%s'", keys(RSASignatureAlgorithms), alg),
 			)
-			return
-		} else { // use = 'sig'
-			resp.Diagnostics.AddWarning(
-				"No 'alg' attribute for 'sig' use",
-				fmt.Sprintf("Consider setting a valid signing algorithm, one of '%s'",
-					keys(RSASignatureAlgorithms)),
+		}
+	case "enc":
+		if _, exists := RSAEncryptionAlgorithms[alg]; !exists {
+			diags.AddAttributeError(
+				path.Root("alg"),
+				"Invalid 'alg' attribute for use: 'enc'",
+				fmt.Sprintf("Expected a valid RSA encryption algorithm %s, got '%s'", keys(RSAEncryptionAlgorithms), alg),
 			)
-			return
 		}
 	}
+
+	return diags
 }
@@ -0,0 +1,711 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Allowed key types (kty) of rotating keys
+var validKeyTypes = []string{"EC", "OKP", "RSA", "oct"}
+
+// Algorithms allowed for each key type and use
+var keyTypeAlgorithms = map[string]map[string]map[string]int{
+	"EC":  {"sig": ECSigAlgorithms, "enc": ECEncAlgorithms},
+	"OKP": {"sig": OKPSigAlgorithms, "enc": ECEncAlgorithms},
+	"RSA": {"sig": RSASignatureAlgorithms, "enc": RSAEncryptionAlgorithms},
+	"oct": {"sig": OCTSignatureAlgorithms, "enc": OCTSEncryptionAlgorithms},
+}
+
+// Number of previous keys kept, when 'keep_previous' is not given
+const defaultKeepPrevious = 1
+
+// Key of the private state, which records the decision of the plan to rotate the key
+const rotatePrivateKey = "rotate"
+
+// Type of a single element in 'keys' attribute
+var rotatingKeyEntryType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"kid":     types.StringType,
+		"created": types.StringType,
+		"json":    types.StringType,
+	},
+}
+
+// Creates a new instance of the jwkRotatingKeyResource.
+func NewJwkRotatingKeyResource() resource.Resource {
+	return &jwkRotatingKeyResource{}
+}
+
+// jwkRotatingKeyResource is a custom resource that regenerates a key periodically and keeps the previous keys.
+type jwkRotatingKeyResource struct {
+	providerData *jwkProviderData
+}
+
+// This struct gets populated with the configuration values
+type jwkRotatingKeyModel struct {
+	KIDPrefix        types.String `tfsdk:"kid_prefix"`
+	Kty              types.String `tfsdk:"kty"`
+	Use              types.String `tfsdk:"use"`
+	Alg              types.String `tfsdk:"alg"`
+	Crv              types.String `tfsdk:"crv"`
+	Size             types.Int64  `tfsdk:"size"`
+	RotationPeriod   types.String `tfsdk:"rotation_period"`
+	KeepPrevious     types.Int64  `tfsdk:"keep_previous"`
+	Keepers          types.Map    `tfsdk:"keepers"`
+	Generation       types.Int64  `tfsdk:"generation"`
+	NextRotation     types.String `tfsdk:"next_rotation"`
+	CurrentKID       types.String `tfsdk:"current_kid"`
+	CurrentJSON      types.String `tfsdk:"current_json"`
+	PreviousJSON     types.List   `tfsdk:"previous_json"`
+	Keys             types.List   `tfsdk:"keys"`
+	KeysetJSON       types.String `tfsdk:"json"`
+	PublicKeysetJSON types.String `tfsdk:"public_json"`
+}
+
+// A generated key with its creation time
+type jwkRotatingKeyEntryModel struct {
+	KID     types.String `tfsdk:"kid"`
+	Created types.String `tfsdk:"created"`
+	KeyJSON types.String `tfsdk:"json"`
+}
+
+// Resource Documentation
+func (r *jwkRotatingKeyResource) Documentation() string {
+	return `This resource creates a key and replaces it with a new one, when 'rotation_period' has elapsed.
+The rotation is detected during plan, so every plan after the due time shows the key being rotated.
+The previous keys are kept in state together with their creation times, so that tokens signed with
+them can still be verified during the overlap. The key ID of each key is '<kid_prefix>-<generation>'.
+Changing the key specification ('kid_prefix', 'kty', 'use', 'alg', 'crv' or 'size') or 'keepers' rotates the key as well.`
+}
+
+// Resource Metadata
+func (r *jwkRotatingKeyResource) Metadata(_ context.Context, _ resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "jwk_rotating_key"
+}
+
+// Configure
+func (r *jwkRotatingKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.providerData = resourceProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+// Resource Schema
+func (r *jwkRotatingKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: r.Documentation(),
+
+		Attributes: map[string]schema.Attribute{
+			"kid_prefix": schema.StringAttribute{
+				Required:    true,
+				Description: "Prefix of the Key IDs. The generation number of the key is appended to it, e.g. `sign-1`, `sign-2`.",
+			},
+			"kty": schema.StringAttribute{
+				Required:    true,
+				Description: fmt.Sprintf("Type of the generated keys, one of `%s`.", strings.Join(validKeyTypes, "`, `")),
+				Validators:  []validator.String{stringOneOf(validKeyTypes...)},
+			},
+			"use": schema.StringAttribute{
+				Required:    true,
+				Description: "Specifies the intended use of the keys. Allowed values: `sig` (for signing) and `enc` (for encryption).",
+				Validators:  []validator.String{stringOneOf(validUses...)},
+			},
+			"alg": schema.StringAttribute{
+				Optional:    true,
+				Description: "The cryptographic algorithm associated with the keys. Allowed values depend on `kty` and `use`, as in the corresponding key resources.",
+			},
+			"crv": schema.StringAttribute{
+				Optional:    true,
+				Description: "Curve of `EC` and `OKP` keys. Defaults to the curve required by `alg`, or to `P-256` (`EC`), `Ed25519` (`OKP`, `sig`) and `X25519` (`OKP`, `enc`).",
+			},
+			"size": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Size of `RSA` and `oct` keys in bits. Defaults to 2048 (`RSA`) and 256 (`oct`).",
+			},
+			"rotation_period": schema.StringAttribute{
+				Required:    true,
+				Description: "Time after which the key is rotated, as a duration, e.g. `90d` or `720h`.",
+			},
+			"keep_previous": schema.Int64Attribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Number of previous keys kept after rotation. Defaults to %d.", defaultKeepPrevious),
+				Validators:  []validator.Int64{int64AtLeast(0)},
+			},
+			"keepers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary map of values that, when changed, will rotate the key before the rotation period has passed. " +
+					"The previous keys are kept as on a regular rotation.",
+			},
+			"generation": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Generation number of the current key. Starts from 1 and is incremented on every rotation.",
+			},
+			"next_rotation": schema.StringAttribute{
+				Computed:    true,
+				Description: "Time (RFC 3339), when the current key is due for rotation.",
+			},
+			"current_kid": schema.StringAttribute{
+				Computed:    true,
+				Description: "Key ID of the current key.",
+			},
+			"current_json": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+				Description: "The JSON representation of the current key. Use this key for signing or decryption. " +
+					"Encrypted as a JWE, when `state_encryption` is configured in the provider, see the `decrypt_state` function.",
+			},
+			"previous_json": schema.ListAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: "The JSON representations of the previous keys, the most recent first. Encrypted as JWEs, when `state_encryption` is configured in the provider.",
+			},
+			"keys": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The current and previous keys, the current key first.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"kid": schema.StringAttribute{
+							Computed:    true,
+							Description: "Key ID of the key.",
+						},
+						"created": schema.StringAttribute{
+							Computed:    true,
+							Description: "Creation time of the key (RFC 3339).",
+						},
+						"json": schema.StringAttribute{
+							Computed:    true,
+							Sensitive:   true,
+							Description: "The JSON representation of the key. Encrypted as a JWE, when `state_encryption` is configured in the provider.",
+						},
+					},
+				},
+			},
+			"json": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "A Json representation of the JWK key set containing the current and previous keys. Encrypted as a JWE, when `state_encryption` is configured in the provider.",
+			},
+			"public_json": schema.StringAttribute{
+				Computed:    true,
+				Description: "A Json representation of the JWK key set containing the public forms of the current and previous keys. Suitable to be published e.g. as jwks_uri. A key set without keys for `oct` keys.",
+			},
+		},
+	}
+}
+
+// Create generates the first key
+func (r *jwkRotatingKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model jwkRotatingKeyModel
+
+	diags := req.Plan.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	entry, err := generateRotatingKey(model, 1, r.providerData.encryption())
+	if err != nil {
+		resp.Diagnostics.AddError("Key Generation Failed", err.Error())
+		return
+	}
+
+	model.Generation = types.Int64Value(1)
+	resp.Diagnostics.Append(model.setKeys(ctx, []jwkRotatingKeyEntryModel{entry}, r.providerData.encryption())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *jwkRotatingKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model jwkRotatingKeyModel
+
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var entries []jwkRotatingKeyEntryModel
+	resp.Diagnostics.Append(model.Keys.ElementsAs(ctx, &entries, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for i, entry := range entries {
+		stored := entry.KeyJSON.ValueString()
+		keyJSON, err := r.providerData.encryption().open(stored)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to decrypt JWK in state", fmt.Sprintf("Key '%s': %s", entry.KID.ValueString(), err.Error()))
+			return
+		}
+
+		// Verify the key is still valid and consistent. Previous keys may be of another type than the current key.
+		key, err := json2jwk(keyJSON)
+		if err == nil {
+			key, err = parseStateKey(keyJSON, key.KeyType())
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid JWK in state", fmt.Sprintf("Key '%s': %s", entry.KID.ValueString(), err.Error()))
+			return
+		}
+
+		// Key specification follows the current key, so that drift shows up in plan and rotates the key
+		if i == 0 {
+			model.setSpecification(key)
+		}
+
+		// Encrypt keys stored before state encryption was enabled
+		if r.providerData.encryption().enabled() && !isSealed(stored) {
+			sealedJSON, err := r.providerData.encryption().seal(keyJSON)
+			if err != nil {
+				resp.Diagnostics.AddError("Failed to encrypt JWK in state", err.Error())
+				return
+			}
+			entries[i].KeyJSON = types.StringValue(sealedJSON)
+		}
+	}
+
+	// Attributes follow the keys, e.g. the key set is encrypted, when state encryption was enabled
+	resp.Diagnostics.Append(model.setKeys(ctx, entries, r.providerData.encryption())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update rotates the key, when the plan has decided so. Otherwise only the number of
+// kept keys and the rotation time are updated.
+func (r *jwkRotatingKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model, state jwkRotatingKeyModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var entries []jwkRotatingKeyEntryModel
+	resp.Diagnostics.Append(state.Keys.ElementsAs(ctx, &entries, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rotate, diags := req.Private.GetKey(ctx, rotatePrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.Generation = state.Generation
+	if string(rotate) == "true" {
+		generation := state.Generation.ValueInt64() + 1
+
+		entry, err := generateRotatingKey(model, generation, r.providerData.encryption())
+		if err != nil {
+			resp.Diagnostics.AddError("Key Generation Failed", err.Error())
+			return
+		}
+
+		entries = append([]jwkRotatingKeyEntryModel{entry}, entries...)
+		model.Generation = types.Int64Value(generation)
+
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, rotatePrivateKey, nil)...)
+	}
+
+	resp.Diagnostics.Append(model.setKeys(ctx, entries, r.providerData.encryption())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, model)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *jwkRotatingKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// -----------------------------------------------------------------------------
+// ---    Plan Modification    -------------------------------------------------
+// -----------------------------------------------------------------------------
+
+// ModifyPlan checks the key specification against the policy of the provider, and decides
+// whether the key is rotated. The decision is recorded in the private state for Update.
+// On rotation the generated values are left unknown, otherwise they are computed from the
+// current state.
+func (r *jwkRotatingKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state jwkRotatingKeyModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Policy of the provider applies to the generated keys, with the default curve and size
+	if !plan.Kty.IsUnknown() && !plan.Use.IsUnknown() && !plan.Alg.IsUnknown() && !plan.Crv.IsUnknown() && !plan.Size.IsUnknown() {
+		kty, use, alg := plan.Kty.ValueString(), plan.Use.ValueString(), plan.Alg.ValueString()
+
+		checked := policyKey{kty: kty, use: use, alg: alg, crv: plan.Crv.ValueString(), size: plan.Size.ValueInt64()}
+		if checked.crv == "" {
+			checked.crv = defaultKeyCurve(kty, use, alg)
+		}
+		if checked.size == 0 {
+			checked.size = int64(defaultKeySize(kty))
+		}
+
+		resp.Diagnostics.Append(r.providerData.keyPolicy().checkKey(checked)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Nothing more to do on create
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	specChanged := !plan.KIDPrefix.Equal(state.KIDPrefix) ||
+		!plan.Kty.Equal(state.Kty) ||
+		!plan.Use.Equal(state.Use) ||
+		!plan.Alg.Equal(state.Alg) ||
+		!plan.Crv.Equal(state.Crv) ||
+		!plan.Size.Equal(state.Size) ||
+		!plan.Keepers.Equal(state.Keepers)
+
+	nextRotation, err := time.Parse(time.RFC3339, state.NextRotation.ValueString())
+	rotationDue := err != nil || !time.Now().Before(nextRotation)
+
+	if specChanged || rotationDue {
+		plan.Generation = types.Int64Unknown()
+		plan.NextRotation = types.StringUnknown()
+		plan.CurrentKID = types.StringUnknown()
+		plan.CurrentJSON = types.StringUnknown()
+		plan.PreviousJSON = types.ListUnknown(types.StringType)
+		plan.Keys = types.ListUnknown(rotatingKeyEntryType)
+		plan.KeysetJSON = types.StringUnknown()
+		plan.PublicKeysetJSON = types.StringUnknown()
+
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, rotatePrivateKey, []byte("true"))...)
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+		return
+	}
+
+	// Private state is carried over from the state, so a decision of an earlier plan is removed
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, rotatePrivateKey, nil)...)
+
+	// The current key is kept
+	plan.Generation = state.Generation
+	plan.CurrentKID = state.CurrentKID
+	plan.CurrentJSON = state.CurrentJSON
+
+	// Rotation period or number of kept keys may change, or they may be unknown yet
+	if plan.RotationPeriod.IsUnknown() || plan.KeepPrevious.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+		return
+	}
+
+	var entries []jwkRotatingKeyEntryModel
+	resp.Diagnostics.Append(state.Keys.ElementsAs(ctx, &entries, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Encryption is not deterministic, so a key set, which changes, is known only after apply
+	plan.KeysetJSON = state.KeysetJSON
+	resp.Diagnostics.Append(plan.setKeys(ctx, entries, r.providerData.encryption())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if r.providerData.encryption().enabled() && !plan.KeysetJSON.Equal(state.KeysetJSON) {
+		plan.KeysetJSON = types.StringUnknown()
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+// -----------------------------------------------------------------------------
+// ---    Validate Configuration    --------------------------------------------
+// -----------------------------------------------------------------------------
+
+func (r jwkRotatingKeyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var model jwkRotatingKeyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !model.RotationPeriod.IsUnknown() {
+		if _, err := parsePeriod(model.RotationPeriod.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("rotation_period"), "Invalid 'rotation_period' attribute", err.Error())
+		}
+	}
+
+	// 'kty', 'use' and 'keep_previous' are checked by the validators of the attributes.
+	// The rest of the checks depend on key type and use.
+	if model.Kty.IsUnknown() || model.Use.IsUnknown() || !isValid(model.Kty.ValueString(), validKeyTypes) || !isValid(model.Use.ValueString(), validUses) {
+		return
+	}
+
+	kty := model.Kty.ValueString()
+	use := model.Use.ValueString()
+
+	if !model.Alg.IsNull() && !model.Alg.IsUnknown() && model.Alg.ValueString() != "" {
+		algorithms := keyTypeAlgorithms[kty][use]
+		if _, ok := algorithms[model.Alg.ValueString()]; !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("alg"),
+				fmt.Sprintf("Invalid 'alg' attribute for use: '%s'", use),
+				fmt.Sprintf("Expected one of %v for key type '%s', got '%s'", keys(algorithms), kty, model.Alg.ValueString()),
+			)
+		}
+	}
+
+	if !model.Crv.IsNull() && !model.Crv.IsUnknown() {
+		var curves []string
+		switch {
+		case kty == "EC" && use == "sig":
+			curves = validECCurves
+		case kty == "EC":
+			curves = validECEncryptionCurves
+		case kty == "OKP" && use == "sig":
+			curves = validOKPSigningCurves
+		case kty == "OKP":
+			curves = validOKPEncryptionCurves
+		}
+
+		if curves == nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("crv"),
+				"Invalid 'crv' attribute",
+				fmt.Sprintf("'crv' is not applicable to key type '%s'", kty),
+			)
+		} else if !isValid(model.Crv.ValueString(), curves) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("crv"),
+				fmt.Sprintf("Invalid 'crv' attribute for use: '%s'", use),
+				fmt.Sprintf("Expected one of %v, got '%s'", curves, model.Crv.ValueString()),
+			)
+		}
+	}
+
+	if !model.Size.IsNull() && !model.Size.IsUnknown() {
+		bits := model.Size.ValueInt64()
+		switch kty {
+		case "RSA":
+			if bits < 2048 {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("size"),
+					"Invalid attribute value for 'size'",
+					fmt.Sprintf("size must be at least 2048, got '%d'", bits),
+				)
+			}
+		case "oct":
+			if bits <= 0 || bits%8 != 0 {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("size"),
+					"Invalid attribute value for 'size'",
+					fmt.Sprintf("size must be positive and divisible by 8, got '%d'", bits),
+				)
+			}
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("size"),
+				"Invalid 'size' attribute",
+				fmt.Sprintf("'size' is not applicable to key type '%s'", kty),
+			)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// ---    Helpers    -----------------------------------------------------------
+// -----------------------------------------------------------------------------
+
+// Generate a key of given generation according to the key specification of the model.
+// The key is encrypted for state, when state encryption is enabled.
+func generateRotatingKey(model jwkRotatingKeyModel, generation int64, encryption *stateEncryption) (jwkRotatingKeyEntryModel, error) {
+	kid := fmt.Sprintf("%s-%d", model.KIDPrefix.ValueString(), generation)
+
+	key, err := generateJWKOfType(model.Kty.ValueString(), kid, model.Use.ValueString(),
+		model.Alg.ValueString(), model.Crv.ValueString(), int(model.Size.ValueInt64()))
+	if err != nil {
+		return jwkRotatingKeyEntryModel{}, err
+	}
+
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		return jwkRotatingKeyEntryModel{}, fmt.Errorf("failed to marshal key: %w", err)
+	}
+
+	sealedJSON, err := encryption.seal(string(keyJSON))
+	if err != nil {
+		return jwkRotatingKeyEntryModel{}, fmt.Errorf("failed to encrypt key for state: %w", err)
+	}
+
+	return jwkRotatingKeyEntryModel{
+		KID:     types.StringValue(kid),
+		Created: types.StringValue(time.Now().UTC().Format(time.RFC3339)),
+		KeyJSON: types.StringValue(sealedJSON),
+	}, nil
+}
+
+// Set the key specification of the model from the current key. Curve and size, which
+// are not given, are kept as long as the key has the default curve and size.
+func (m *jwkRotatingKeyModel) setSpecification(key jwk.Key) {
+	kty := key.KeyType().String()
+
+	m.Kty = keyAttributeValue(m.Kty, kty)
+	m.Use = keyAttributeValue(m.Use, key.KeyUsage())
+	m.Alg = keyAttributeValue(m.Alg, key.Algorithm().String())
+
+	crv := keyCurve(key)
+	if !m.Crv.IsNull() || crv != defaultKeyCurve(kty, m.Use.ValueString(), m.Alg.ValueString()) {
+		m.Crv = keyAttributeValue(m.Crv, crv)
+	}
+
+	size := int64(0)
+	if kty == "RSA" || kty == "oct" {
+		size = keySizeBits(key)
+	}
+	if !m.Size.IsNull() || size != int64(defaultKeySize(kty)) {
+		if size == 0 {
+			m.Size = types.Int64Null()
+		} else if size != m.Size.ValueInt64() {
+			m.Size = types.Int64Value(size)
+		}
+	}
+}
+
+// Set the generated attributes of the model from given keys, the current key first.
+// Keys exceeding 'keep_previous' are dropped. The keys may be encrypted by state encryption,
+// and the key set is encrypted, when it is enabled.
+func (m *jwkRotatingKeyModel) setKeys(ctx context.Context, entries []jwkRotatingKeyEntryModel, encryption *stateEncryption) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	keepPrevious := int64(defaultKeepPrevious)
+	if !m.KeepPrevious.IsNull() {
+		keepPrevious = m.KeepPrevious.ValueInt64()
+	}
+	if int64(len(entries)) > keepPrevious+1 {
+		entries = entries[:keepPrevious+1]
+	}
+
+	period, err := parsePeriod(m.RotationPeriod.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("rotation_period"), "Invalid 'rotation_period' attribute", err.Error())
+		return diags
+	}
+
+	created, err := time.Parse(time.RFC3339, entries[0].Created.ValueString())
+	if err != nil {
+		diags.AddError("Invalid creation time in state", err.Error())
+		return diags
+	}
+
+	storedJSONs := make([]string, 0, len(entries))
+	keyJSONs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keyJSON, err := encryption.open(entry.KeyJSON.ValueString())
+		if err != nil {
+			diags.AddError("Failed to decrypt JWK in state", fmt.Sprintf("Key '%s': %s", entry.KID.ValueString(), err.Error()))
+			return diags
+		}
+		storedJSONs = append(storedJSONs, entry.KeyJSON.ValueString())
+		keyJSONs = append(keyJSONs, keyJSON)
+	}
+
+	keyList, d := types.ListValueFrom(ctx, types.StringType, keyJSONs)
+	diags.Append(d...)
+	m.PreviousJSON, d = types.ListValueFrom(ctx, types.StringType, storedJSONs[1:])
+	diags.Append(d...)
+	m.Keys, d = types.ListValueFrom(ctx, rotatingKeyEntryType, entries)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	keysetJSON, err := createJWKKeyset(keyList)
+	if err != nil {
+		diags.AddError("Failed to create JWK Keyset", err.Error())
+		return diags
+	}
+
+	publicKeysetJSON, err := createPublicJWKKeyset(keyList, octKeysDrop)
+	if err != nil {
+		diags.AddError("Failed to create public JWK Keyset", err.Error())
+		return diags
+	}
+
+	// Encryption is not deterministic, so the key set is encrypted again only, when it
+	// has changed, or was stored before state encryption was enabled
+	stored := m.KeysetJSON.ValueString()
+	storedJSON, err := encryption.open(stored)
+	if m.KeysetJSON.IsUnknown() || m.KeysetJSON.IsNull() || err != nil || storedJSON != keysetJSON || (encryption.enabled() && !isSealed(stored)) {
+		sealedJSON, err := encryption.seal(keysetJSON)
+		if err != nil {
+			diags.AddError("Failed to encrypt JWK Keyset for state", err.Error())
+			return diags
+		}
+		m.KeysetJSON = types.StringValue(sealedJSON)
+	}
+
+	m.NextRotation = types.StringValue(created.Add(period).UTC().Format(time.RFC3339))
+	m.CurrentKID = entries[0].KID
+	m.CurrentJSON = entries[0].KeyJSON
+	m.PublicKeysetJSON = types.StringValue(publicKeysetJSON)
+
+	return diags
+}
+
+// Parse a period, such as rotation period. In addition to Go durations, whole days are accepted, e.g. '90d'.
+func parsePeriod(value string) (time.Duration, error) {
+	var period time.Duration
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("expected a duration such as '90d' or '720h', got '%s'", value)
+		}
+		period = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("expected a duration such as '90d' or '720h', got '%s'", value)
+		}
+		period = d
+	}
+
+	if period <= 0 {
+		return 0, fmt.Errorf("expected a positive duration, got '%s'", value)
+	}
+	return period, nil
+}
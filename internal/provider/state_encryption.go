@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwe"
+)
+
+// Minimum length of the state encryption passphrase
+const minStatePassphraseLength = 16
+
+// Encryption of private keys in state, configured by 'state_encryption' of the provider.
+// Private keys are stored as compact JWE, using PBES2-HS512+A256KW with a passphrase,
+// or A256KW with a symmetric key encryption key (KEK). A nil *stateEncryption stores
+// private keys in plaintext.
+type stateEncryption struct {
+	alg jwa.KeyEncryptionAlgorithm
+	key []byte
+}
+
+// Create state encryption from a passphrase or a KEK in JWK format. Exactly one of them must be given.
+func newStateEncryption(passphrase string, kek string) (*stateEncryption, error) {
+	switch {
+	case passphrase != "" && kek != "":
+		return nil, fmt.Errorf("give either 'passphrase' or 'key', not both")
+
+	case passphrase != "":
+		if len(passphrase) < minStatePassphraseLength {
+			return nil, fmt.Errorf("passphrase must be at least %d characters long", minStatePassphraseLength)
+		}
+		return &stateEncryption{alg: jwa.PBES2_HS512_A256KW, key: []byte(passphrase)}, nil
+
+	case kek != "":
+		key, err := json2jwk(kek)
+		if err != nil {
+			return nil, err
+		}
+		if key.KeyType() != jwa.OctetSeq {
+			return nil, fmt.Errorf("key encryption key must be a symmetric (oct) key, got '%s'", key.KeyType())
+		}
+		if alg := key.Algorithm().String(); alg != "" && alg != jwa.A256KW.String() {
+			return nil, fmt.Errorf("key encryption key must have 'alg' %s, got '%s'", jwa.A256KW, alg)
+		}
+		var raw []byte
+		if err := key.Raw(&raw); err != nil {
+			return nil, fmt.Errorf("failed to get key encryption key: %w", err)
+		}
+		if len(raw) != 32 {
+			return nil, fmt.Errorf("key encryption key must be 256 bits for %s, got %d bits", jwa.A256KW, len(raw)*8)
+		}
+		return &stateEncryption{alg: jwa.A256KW, key: raw}, nil
+
+	default:
+		return nil, fmt.Errorf("give either 'passphrase' or 'key'")
+	}
+}
+
+// Create state encryption from the secret given to the 'decrypt_state' function, which
+// is a KEK, when it is a JSON object, otherwise a passphrase.
+func stateEncryptionFromSecret(secret string) (*stateEncryption, error) {
+	if strings.HasPrefix(strings.TrimSpace(secret), "{") {
+		return newStateEncryption("", secret)
+	}
+	return newStateEncryption(secret, "")
+}
+
+// Whether private keys are encrypted in state
+func (e *stateEncryption) enabled() bool {
+	return e != nil
+}
+
+// Encrypt a value for state. Returns the value as is, when state encryption is not enabled.
+func (e *stateEncryption) seal(value string) (string, error) {
+	if e == nil {
+		return value, nil
+	}
+
+	encrypted, err := jwe.Encrypt([]byte(value),
+		jwe.WithKey(e.alg, e.key),
+		jwe.WithContentEncryption(jwa.A256GCM),
+		jwe.WithCompact(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt: %w", err)
+	}
+
+	return string(encrypted), nil
+}
+
+// Decrypt a value from state. Plaintext values, e.g. stored before state encryption was
+// enabled, are returned as is.
+func (e *stateEncryption) open(value string) (string, error) {
+	if !isSealed(value) {
+		return value, nil
+	}
+	if e == nil {
+		return "", fmt.Errorf("value is encrypted, but 'state_encryption' is not configured in the provider")
+	}
+
+	plaintext, err := jwe.Decrypt([]byte(strings.TrimSpace(value)), jwe.WithKey(e.alg, e.key))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt with the given passphrase or key: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// Value of a private attribute other than 'json', e.g. a private key PEM. These are
+// not stored in state, when state encryption is enabled.
+func (e *stateEncryption) privateValue(value string) types.String {
+	if e != nil {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// Whether a value from state is a JWE in compact serialization rather than plaintext JSON
+func isSealed(value string) bool {
+	value = strings.TrimSpace(value)
+	return value != "" && !strings.HasPrefix(value, "{")
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// Validates, that a string is one of the given values. Like all validators of the
+// provider, it skips null and unknown values, which are checked when they are known.
+type stringOneOfValidator struct {
+	values []string
+}
+
+// Validator of a string, which is one of the given values
+func stringOneOf(values ...string) validator.String {
+	return stringOneOfValidator{values: values}
+}
+
+func (v stringOneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("Value must be one of `%s`.", strings.Join(v.values, "`, `"))
+}
+
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringOneOfValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueString(); !isValid(value, v.values) {
+		addInvalidValueError(&resp.Diagnostics, req.Path,
+			fmt.Sprintf("Expected one of %v, got '%s'", v.values, value))
+	}
+}
+
+// Validates, that a number is at least the given minimum
+type int64AtLeastValidator struct {
+	min int64
+}
+
+// Validator of a number, which is at least the given minimum
+func int64AtLeast(min int64) validator.Int64 {
+	return int64AtLeastValidator{min: min}
+}
+
+func (v int64AtLeastValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("Value must be at least %d.", v.min)
+}
+
+func (v int64AtLeastValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int64AtLeastValidator) ValidateInt64(_ context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueInt64(); value < v.min {
+		addInvalidValueError(&resp.Diagnostics, req.Path,
+			fmt.Sprintf("Expected at least %d, got %d", v.min, value))
+	}
+}
+
+// Validates, that a number is a positive multiple of the given factor, e.g. a size in bits of whole bytes
+type int64MultipleOfValidator struct {
+	factor int64
+}
+
+// Validator of a number, which is a positive multiple of the given factor
+func int64MultipleOf(factor int64) validator.Int64 {
+	return int64MultipleOfValidator{factor: factor}
+}
+
+func (v int64MultipleOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("Value must be a positive multiple of %d.", v.factor)
+}
+
+func (v int64MultipleOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int64MultipleOfValidator) ValidateInt64(_ context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueInt64(); value <= 0 || value%v.factor != 0 {
+		addInvalidValueError(&resp.Diagnostics, req.Path,
+			fmt.Sprintf("Expected a positive multiple of %d, got %d", v.factor, value))
+	}
+}
+
+// Report an invalid value on the attribute, named by its path, e.g. 'use' or 'filter.use'
+func addInvalidValueError(diags *diag.Diagnostics, at path.Path, detail string) {
+	diags.AddAttributeError(at, fmt.Sprintf("Invalid attribute value for '%s'", at), detail)
+}